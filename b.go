@@ -0,0 +1,259 @@
+package mocktesting
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// TimerEvent is a change in the state of the benchmark timer of a *B instance.
+type TimerEvent string
+
+const (
+	// TimerStart is recorded when StartTimer() starts a stopped timer.
+	TimerStart TimerEvent = "start"
+
+	// TimerStop is recorded when StopTimer() stops a running timer.
+	TimerStop TimerEvent = "stop"
+
+	// TimerReset is recorded each time ResetTimer() is called.
+	TimerReset TimerEvent = "reset"
+)
+
+// B is a fake/mock implementation of *testing.B. It embeds *T, so all methods
+// and inspection methods of *T are also available on *B, with the exception of
+// Run(), which accepts a func(*B) instead.
+//
+// Like *T, all method calls against *B are recorded, so they can be inspected
+// and asserted later. As *testing.B exposes the iteration count as the N field
+// rather than a method, benchmark helpers wishing to accept both *testing.B and
+// *mocktesting.B will need to receive N separately, or use a custom interface
+// alongside it.
+type B struct {
+	// N is the number of iterations the benchmark function should perform. It
+	// defaults to 1, and can be set with the WithN() option.
+	N int
+
+	// State - Fields which record how B has been modified via method calls.
+	timerOn     bool
+	timerEvents []TimerEvent
+	allocs      bool
+	bytes       int64
+	metrics     map[string]float64
+	benchmarks  []*B
+	loops       int
+
+	*T
+}
+
+// NewB returns a new *B instance with the given name, configured with the
+// given options. Options which only apply to *T, like WithParallelSubtests(),
+// are accepted but have no effect on *B itself.
+func NewB(name string, options ...Option) *B {
+	return newB(NewT(name, options...))
+}
+
+func newB(t *T) *B {
+	n := t.benchN
+	if n < 1 {
+		n = 1
+	}
+
+	// Benchmarks cannot be run in parallel with each other, so sub-benchmarks
+	// calling Parallel() must never be paused.
+	t.pause = false
+
+	return &B{N: n, T: t, timerOn: true}
+}
+
+// WithN sets the N field of *B instances, determining the number of iterations
+// benchmark functions perform, and the number of times Loop() returns true.
+// Sub-benchmarks created with Run() inherit the value.
+//
+// If this option is not used, N defaults to 1.
+func WithN(n int) Option {
+	return optionFunc(func(t *T) {
+		t.benchN = n
+	})
+}
+
+// ResetTimer records that the benchmark timer has been reset. The timer's
+// running state is not changed.
+func (b *B) ResetTimer() {
+	b.record("ResetTimer")
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.timerEvents = append(b.timerEvents, TimerReset)
+}
+
+// StartTimer marks the benchmark timer as running. A TimerStart event is
+// recorded if the timer was stopped.
+func (b *B) StartTimer() {
+	b.record("StartTimer")
+	b.startTimer()
+}
+
+func (b *B) startTimer() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if !b.timerOn {
+		b.timerOn = true
+		b.timerEvents = append(b.timerEvents, TimerStart)
+	}
+}
+
+// StopTimer marks the benchmark timer as stopped. A TimerStop event is
+// recorded if the timer was running.
+func (b *B) StopTimer() {
+	b.record("StopTimer")
+	b.stopTimer()
+}
+
+func (b *B) stopTimer() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.timerOn {
+		b.timerOn = false
+		b.timerEvents = append(b.timerEvents, TimerStop)
+	}
+}
+
+// ReportAllocs records that memory allocation statistics have been requested.
+// Use AllocsReported() to check if ReportAllocs() has been called.
+func (b *B) ReportAllocs() {
+	b.record("ReportAllocs")
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.allocs = true
+}
+
+// SetBytes records the number of bytes processed in a single iteration, which
+// can be inspected with Bytes().
+func (b *B) SetBytes(n int64) {
+	b.record("SetBytes", n)
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.bytes = n
+}
+
+// ReportMetric records the given metric value for the given unit, replacing any
+// value previously reported for the same unit. Metrics can be inspected with
+// Metrics().
+//
+// Like *testing.B, it panics if unit is empty or contains whitespace.
+func (b *B) ReportMetric(n float64, unit string) {
+	b.record("ReportMetric", n, unit)
+
+	if unit == "" {
+		panic("metric unit must not be empty")
+	}
+	if strings.IndexFunc(unit, unicode.IsSpace) >= 0 {
+		panic("metric unit must not contain whitespace")
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.metrics == nil {
+		b.metrics = map[string]float64{}
+	}
+	b.metrics[unit] = n
+}
+
+// Run runs f as a sub-benchmark in a separate blocking goroutine, just like
+// Run() on *T does for sub-tests. The sub-benchmark receives a new *B instance
+// which inherits N and all other settings from the parent.
+//
+// The list of sub-benchmark *B instances can be accessed with Benchmarks(), and
+// their underlying *T instances are also available from Subtests().
+func (b *B) Run(name string, f func(*B)) bool {
+	b.record("Run", name, f)
+
+	sub := newB(b.newSubtest(name))
+
+	b.mux.Lock()
+	b.benchmarks = append(b.benchmarks, sub)
+	b.mux.Unlock()
+
+	return b.runSubtest(sub.T, func() {
+		f(sub)
+	})
+}
+
+//
+// Inspection Methods which are not part of the *testing.B type.
+//
+
+// TimerRunning returns true if the benchmark timer is running. The timer is
+// running when a *B instance is created.
+func (b *B) TimerRunning() bool {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.timerOn
+}
+
+// TimerEvents returns a slice of changes made to the benchmark timer via
+// StartTimer(), StopTimer() and ResetTimer(), in the order they happened.
+func (b *B) TimerEvents() []TimerEvent {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.timerEvents
+}
+
+// AllocsReported returns true if ReportAllocs() has been called.
+func (b *B) AllocsReported() bool {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.allocs
+}
+
+// Bytes returns the value last given to SetBytes().
+func (b *B) Bytes() int64 {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.bytes
+}
+
+// Metrics returns a map of units to values reported with ReportMetric().
+func (b *B) Metrics() map[string]float64 {
+	if b.metrics == nil {
+		b.mux.Lock()
+		b.metrics = map[string]float64{}
+		b.mux.Unlock()
+	}
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.metrics
+}
+
+// Benchmarks returns a slice of *B instances created for any sub-benchmarks
+// executed via Run().
+func (b *B) Benchmarks() []*B {
+	if b.benchmarks == nil {
+		b.mux.Lock()
+		b.benchmarks = []*B{}
+		b.mux.Unlock()
+	}
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.benchmarks
+}
+
+// Ensure B struct implements testing.TB interface.
+var _ testing.TB = (*B)(nil)
@@ -0,0 +1,41 @@
+package mocktesting_test
+
+import (
+	"fmt"
+
+	"github.com/jimeh/go-mocktesting"
+)
+
+func ExampleB() {
+	benchmarkSum := func(b *mocktesting.B, values []int) {
+		b.ReportAllocs()
+		b.StopTimer()
+		b.SetBytes(int64(len(values)))
+		b.StartTimer()
+
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for _, v := range values {
+				sum += v
+			}
+			b.ReportMetric(float64(sum), "sum")
+		}
+	}
+
+	mb := mocktesting.NewB("BenchmarkSum", mocktesting.WithN(3))
+	benchmarkSum(mb, []int{1, 2, 3})
+	fmt.Printf("Name: %s\n", mb.Name())
+	fmt.Printf("AllocsReported: %+v\n", mb.AllocsReported())
+	fmt.Printf("Bytes: %d\n", mb.Bytes())
+	fmt.Printf("TimerEvents: %+v\n", mb.TimerEvents())
+	fmt.Printf("Metrics: %+v\n", mb.Metrics())
+	fmt.Printf("ReportMetric calls: %d\n", len(mb.CallsTo("ReportMetric")))
+
+	// Output:
+	// Name: BenchmarkSum
+	// AllocsReported: true
+	// Bytes: 3
+	// TimerEvents: [stop start]
+	// Metrics: map[sum:6]
+	// ReportMetric calls: 3
+}
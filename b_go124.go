@@ -0,0 +1,40 @@
+//go:build go1.24
+// +build go1.24
+
+package mocktesting
+
+// Loop returns true N times, allowing it to be used as the condition of a
+// benchmark loop, just like *testing.B. The first call resets and starts the
+// benchmark timer, and the timer is stopped once Loop() returns false.
+//
+// The number of times Loop() has returned true can be inspected with Loops().
+func (b *B) Loop() bool {
+	b.record("Loop")
+
+	b.mux.Lock()
+	first := b.loops == 0
+	done := b.loops >= b.N
+	if first {
+		b.timerEvents = append(b.timerEvents, TimerReset)
+	}
+	if !done {
+		b.loops++
+	}
+	b.mux.Unlock()
+
+	if done {
+		b.stopTimer()
+	} else if first {
+		b.startTimer()
+	}
+
+	return !done
+}
+
+// Loops returns the number of times Loop() has returned true.
+func (b *B) Loops() int {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	return b.loops
+}
@@ -0,0 +1,50 @@
+//go:build go1.24
+// +build go1.24
+
+package mocktesting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestB_Loop(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    int
+	}{
+		{
+			name: "default N",
+			want: 1,
+		},
+		{
+			name:    "custom N",
+			options: []Option{WithN(25)},
+			want:    25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewB("BenchmarkLoop", tt.options...)
+			b.StopTimer()
+
+			iterations := 0
+			for b.Loop() {
+				iterations++
+				assert.True(t, b.TimerRunning())
+			}
+
+			assert.Equal(t, tt.want, iterations)
+			assert.Equal(t, tt.want, b.Loops())
+			assert.False(t, b.TimerRunning())
+			assert.Equal(t,
+				[]TimerEvent{TimerStop, TimerReset, TimerStart, TimerStop},
+				b.TimerEvents(),
+			)
+			assert.False(t, b.Loop())
+			assert.Equal(t, tt.want, b.Loops())
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package mocktesting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewB(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		wantN   int
+	}{
+		{
+			name:  "default N",
+			wantN: 1,
+		},
+		{
+			name:    "custom N",
+			options: []Option{WithN(1000)},
+			wantN:   1000,
+		},
+		{
+			name:    "zero N",
+			options: []Option{WithN(0)},
+			wantN:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewB("BenchmarkFoo bar", tt.options...)
+
+			assert.Equal(t, tt.wantN, got.N)
+			assert.Equal(t, "BenchmarkFoo_bar", got.name)
+			assert.True(t, got.timerOn)
+			assert.NotNil(t, got.T)
+		})
+	}
+}
+
+func TestWithN(t *testing.T) {
+	mt := &T{}
+
+	WithN(42).apply(mt)
+
+	assert.Equal(t, 42, mt.benchN)
+}
+
+func TestB_timer(t *testing.T) {
+	tests := []struct {
+		name        string
+		f           func(b *B)
+		wantRunning bool
+		wantEvents  []TimerEvent
+	}{
+		{
+			name:        "no calls",
+			f:           func(b *B) {},
+			wantRunning: true,
+		},
+		{
+			name:        "start running timer",
+			f:           func(b *B) { b.StartTimer() },
+			wantRunning: true,
+		},
+		{
+			name:        "stop",
+			f:           func(b *B) { b.StopTimer() },
+			wantRunning: false,
+			wantEvents:  []TimerEvent{TimerStop},
+		},
+		{
+			name: "stop twice",
+			f: func(b *B) {
+				b.StopTimer()
+				b.StopTimer()
+			},
+			wantRunning: false,
+			wantEvents:  []TimerEvent{TimerStop},
+		},
+		{
+			name: "stop, reset, start",
+			f: func(b *B) {
+				b.StopTimer()
+				b.ResetTimer()
+				b.StartTimer()
+			},
+			wantRunning: true,
+			wantEvents:  []TimerEvent{TimerStop, TimerReset, TimerStart},
+		},
+		{
+			name: "reset while running",
+			f: func(b *B) {
+				b.ResetTimer()
+				b.ResetTimer()
+			},
+			wantRunning: true,
+			wantEvents:  []TimerEvent{TimerReset, TimerReset},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewB("BenchmarkTimer")
+
+			tt.f(b)
+
+			assert.Equal(t, tt.wantRunning, b.TimerRunning())
+			assert.Equal(t, tt.wantEvents, b.TimerEvents())
+		})
+	}
+}
+
+func TestB_ReportAllocs(t *testing.T) {
+	b := NewB("BenchmarkAllocs")
+	assert.False(t, b.AllocsReported())
+
+	b.ReportAllocs()
+
+	assert.True(t, b.AllocsReported())
+	assert.Len(t, b.CallsTo("ReportAllocs"), 1)
+}
+
+func TestB_SetBytes(t *testing.T) {
+	b := NewB("BenchmarkBytes")
+	assert.Equal(t, int64(0), b.Bytes())
+
+	b.SetBytes(1024)
+	b.SetBytes(2048)
+
+	assert.Equal(t, int64(2048), b.Bytes())
+	calls := b.CallsTo("SetBytes")
+	require.Len(t, calls, 2)
+	assert.Equal(t, []interface{}{int64(1024)}, calls[0].Args)
+}
+
+func TestB_ReportMetric(t *testing.T) {
+	type metric struct {
+		n    float64
+		unit string
+	}
+	tests := []struct {
+		name      string
+		metrics   []metric
+		want      map[string]float64
+		wantPanic interface{}
+	}{
+		{
+			name: "none",
+			want: map[string]float64{},
+		},
+		{
+			name: "many",
+			metrics: []metric{
+				{n: 4, unit: "widgets/op"},
+				{n: 0.5, unit: "hits/op"},
+				{n: 8, unit: "widgets/op"},
+			},
+			want: map[string]float64{"widgets/op": 8, "hits/op": 0.5},
+		},
+		{
+			name:      "empty unit",
+			metrics:   []metric{{n: 1, unit: ""}},
+			want:      map[string]float64{},
+			wantPanic: "metric unit must not be empty",
+		},
+		{
+			name:      "unit with whitespace",
+			metrics:   []metric{{n: 1, unit: "foo bar"}},
+			want:      map[string]float64{},
+			wantPanic: "metric unit must not contain whitespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewB("BenchmarkMetric")
+
+			var p interface{}
+			func() {
+				defer func() { p = recover() }()
+				for _, m := range tt.metrics {
+					b.ReportMetric(m.n, m.unit)
+				}
+			}()
+
+			assert.Equal(t, tt.wantPanic, p)
+			assert.Equal(t, tt.want, b.Metrics())
+		})
+	}
+}
+
+func TestB_Run(t *testing.T) {
+	b := NewB("BenchmarkRun", WithN(50))
+
+	var gotN []int
+	ok1 := b.Run("fast", func(b *B) {
+		gotN = append(gotN, b.N)
+		b.ReportAllocs()
+	})
+	ok2 := b.Run("slow", func(b *B) {
+		gotN = append(gotN, b.N)
+		b.Run("nested", func(b *B) {
+			gotN = append(gotN, b.N)
+			b.Fatal("oops")
+		})
+	})
+
+	assert.True(t, ok1)
+	assert.False(t, ok2)
+	assert.True(t, b.Failed())
+	assert.Equal(t, []int{50, 50, 50}, gotN)
+
+	benchmarks := b.Benchmarks()
+	require.Len(t, benchmarks, 2)
+	assert.Equal(t, "BenchmarkRun/fast", benchmarks[0].Name())
+	assert.True(t, benchmarks[0].AllocsReported())
+	assert.Equal(t, "BenchmarkRun/slow", benchmarks[1].Name())
+	require.Len(t, benchmarks[1].Benchmarks(), 1)
+	nested := benchmarks[1].Benchmarks()[0]
+	assert.Equal(t, "BenchmarkRun/slow/nested", nested.Name())
+	assert.True(t, nested.Failed())
+	assert.True(t, nested.Aborted())
+	assert.Equal(t, []string{"oops\n"}, nested.Output())
+
+	subtests := b.Subtests()
+	require.Len(t, subtests, 2)
+	assert.Same(t, benchmarks[0].T, subtests[0])
+	assert.Same(t, benchmarks[1].T, subtests[1])
+}
+
+func TestB_Run_parallelSubtests(t *testing.T) {
+	b := NewB("BenchmarkRun", WithParallelSubtests())
+
+	var ran bool
+	ok := b.Run("sub", func(b *B) {
+		b.Parallel()
+		ran = true
+	})
+
+	assert.True(t, ok)
+	assert.True(t, ran)
+	require.Len(t, b.Benchmarks(), 1)
+	assert.True(t, b.Benchmarks()[0].Finished())
+}
+
+func TestB_Benchmarks(t *testing.T) {
+	b := &B{T: &T{}}
+	assert.Equal(t, []*B{}, b.Benchmarks())
+
+	sub := &B{T: &T{name: "sub"}}
+	b.benchmarks = []*B{sub}
+	assert.Equal(t, []*B{sub}, b.Benchmarks())
+}
@@ -0,0 +1,179 @@
+package mocktesting
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// callSeq is the sequence number of the last recorded Call. It is shared by all
+// *T instances, allowing calls to be ordered across a tree of sub-tests.
+var callSeq uint64
+
+// nextSeq returns the next number from the sequence used by Call.Seq.
+func nextSeq() uint64 {
+	return atomic.AddUint64(&callSeq, 1)
+}
+
+// Call describes a single call to a testing.TB method on a *T instance.
+type Call struct {
+	// Seq is the sequence number of the call. It is increased for each call
+	// made to any *T instance, so it can be used to order calls made to
+	// different *T instances.
+	Seq uint64
+
+	// Method is the name of the method which was called, for example
+	// "Errorf".
+	Method string
+
+	// Args holds the arguments given to the method. For methods which accept
+	// a format string, like Logf(), the format string is the first element.
+	Args []interface{}
+
+	// File and Line is the location the method was called from.
+	File string
+	Line int
+
+	// Goroutine is the ID of the goroutine the method was called from.
+	Goroutine uint64
+
+	// Time is the time at which the method was called.
+	Time time.Time
+}
+
+// LateCall is a Call made to a *T instance after it has completed, meaning
+// after Finish() has returned. Real *testing.T panics when this happens, as it
+// usually means a goroutine started by the test has outlived it.
+type LateCall struct {
+	Call
+
+	// Stack is the stack trace of the goroutine which made the call.
+	Stack []byte
+}
+
+// lateMethods maps the methods which are reported when called after a *T
+// instance has completed, to the name used in the panic message of
+// *testing.T.
+var lateMethods = map[string]string{
+	"Log":     "Log",
+	"Logf":    "Log",
+	"Error":   "Log",
+	"Errorf":  "Log",
+	"Fatal":   "Log",
+	"Fatalf":  "Log",
+	"Skip":    "Log",
+	"Skipf":   "Log",
+	"Fail":    "Fail",
+	"FailNow": "Fail",
+}
+
+// goexitMethods are the methods which abort the goroutine they are called
+// from, and must only be called from the goroutine running the test function.
+var goexitMethods = map[string]bool{
+	"FailNow": true,
+	"Fatal":   true,
+	"Fatalf":  true,
+	"SkipNow": true,
+	"Skip":    true,
+	"Skipf":   true,
+}
+
+// record adds a Call for the given method and args to the *T instance's call
+// journal. It must be called directly from the exported method being
+// recorded, as the caller of that method is recorded as the call location.
+func (t *T) record(method string, args ...interface{}) Call {
+	c := Call{
+		Seq:       nextSeq(),
+		Method:    method,
+		Args:      args,
+		Goroutine: goroutineID(),
+		Time:      t.now(),
+	}
+	_, c.File, c.Line, _ = runtime.Caller(2)
+
+	kind, late := lateMethods[method]
+	inGo := goroutines.running(c.Goroutine)
+
+	t.mux.Lock()
+	t.calls = append(t.calls, c)
+	late = late && t.completed
+	if late {
+		t.lateCalls = append(t.lateCalls, LateCall{
+			Call:  c,
+			Stack: debug.Stack(),
+		})
+	}
+	latePanic := t.latePanic
+	bind := t.owner == 0 && inGo
+	if bind {
+		t.owner = c.Goroutine
+	}
+	noOwner := goexitMethods[method] && t.owner == 0
+	nonTest := goexitMethods[method] && t.owner != 0 &&
+		c.Goroutine != t.owner
+	if nonTest {
+		t.nonTestCalls = append(t.nonTestCalls, c)
+	}
+	ownerCheck := t.ownerCheck
+	exp := t.expectations
+	t.mux.Unlock()
+
+	if bind {
+		goroutines.bind(c.Goroutine, t)
+	}
+
+	if late && latePanic {
+		msg := kind + " in goroutine after " + t.name + " has completed"
+		if kind == "Log" {
+			msg += ": " + callMessage(c)
+		}
+		panic(msg)
+	}
+
+	if ownerCheck {
+		switch {
+		case nonTest:
+			t.reportf(
+				"call to %s() from a non-test goroutine at %s:%d",
+				method, filepath.Base(c.File), c.Line,
+			)
+		case noOwner:
+			t.reportf(
+				"call to %s() at %s:%d cannot be checked, as %s has no "+
+					"test goroutine; run the test function with Go()",
+				method, filepath.Base(c.File), c.Line, t.name,
+			)
+		}
+	}
+
+	if exp != nil {
+		exp.check(c)
+	}
+
+	return c
+}
+
+// formatArgs returns a slice of the format string followed by args, as used for
+// the Args field of Call for methods like Logf().
+func formatArgs(format string, args []interface{}) []interface{} {
+	return append([]interface{}{format}, args...)
+}
+
+// goroutineID returns the ID of the current goroutine, as parsed from the
+// header of its stack trace.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+
+	return id
+}
@@ -0,0 +1,35 @@
+package mocktesting
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_goroutineID(t *testing.T) {
+	id := goroutineID()
+
+	assert.NotZero(t, id)
+	assert.Equal(t, id, goroutineID())
+
+	var other uint64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		other = goroutineID()
+	}()
+	wg.Wait()
+
+	assert.NotZero(t, other)
+	assert.NotEqual(t, id, other)
+}
+
+func Test_formatArgs(t *testing.T) {
+	assert.Equal(t, []interface{}{""}, formatArgs("", nil))
+	assert.Equal(t,
+		[]interface{}{"%s: %d", "foo", 42},
+		formatArgs("%s: %d", []interface{}{"foo", 42}),
+	)
+}
@@ -0,0 +1,99 @@
+package mocktesting
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by *T, for deadlines, timestamps and
+// timeouts. See WithClock() for more details.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on
+	// the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// FakeClock is a manually controlled Clock implementation. Its time only
+// changes when Advance() or Set() is called, making deadlines, timestamps and
+// timeouts of a *T instance using it fully deterministic.
+type FakeClock struct {
+	mux     sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// Ensure FakeClock struct implements Clock interface.
+var _ Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a new *FakeClock with its current time set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the fake clock.
+func (c *FakeClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.now
+}
+
+// After returns a channel which receives the current time of the fake clock
+// once it has been advanced by at least d. If d is zero or negative, the
+// channel receives immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	w := &fakeWaiter{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- c.now
+
+		return w.c
+	}
+
+	c.waiters = append(c.waiters, w)
+
+	return w.c
+}
+
+// Advance moves the current time of the fake clock forward by d, and fires
+// all channels returned by After() which are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+// Set sets the current time of the fake clock to now, and fires all channels
+// returned by After() which are due.
+func (c *FakeClock) Set(now time.Time) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.set(now)
+}
+
+func (c *FakeClock) set(now time.Time) {
+	c.now = now
+
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(now) {
+			waiters = append(waiters, w)
+
+			continue
+		}
+		w.c <- now
+	}
+	c.waiters = waiters
+}
@@ -0,0 +1,137 @@
+package mocktesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fakeEpoch = time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case v := <-c:
+		return v, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock_Now(t *testing.T) {
+	c := NewFakeClock(fakeEpoch)
+
+	assert.Equal(t, fakeEpoch, c.Now())
+	assert.Equal(t, fakeEpoch, c.Now())
+}
+
+func TestFakeClock_Advance(t *testing.T) {
+	c := NewFakeClock(fakeEpoch)
+
+	c.Advance(5 * time.Second)
+	assert.Equal(t, fakeEpoch.Add(5*time.Second), c.Now())
+
+	c.Advance(1 * time.Hour)
+	assert.Equal(t, fakeEpoch.Add(1*time.Hour+5*time.Second), c.Now())
+}
+
+func TestFakeClock_Set(t *testing.T) {
+	c := NewFakeClock(fakeEpoch)
+	later := fakeEpoch.Add(24 * time.Hour)
+
+	c.Set(later)
+
+	assert.Equal(t, later, c.Now())
+}
+
+func TestFakeClock_After(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        time.Duration
+		advance  func(c *FakeClock)
+		wantFire bool
+		wantTime time.Time
+	}{
+		{
+			name:     "zero duration",
+			d:        0,
+			advance:  func(c *FakeClock) {},
+			wantFire: true,
+			wantTime: fakeEpoch,
+		},
+		{
+			name:     "negative duration",
+			d:        -1 * time.Second,
+			advance:  func(c *FakeClock) {},
+			wantFire: true,
+			wantTime: fakeEpoch,
+		},
+		{
+			name:     "not advanced",
+			d:        1 * time.Second,
+			advance:  func(c *FakeClock) {},
+			wantFire: false,
+		},
+		{
+			name: "advanced too little",
+			d:    10 * time.Second,
+			advance: func(c *FakeClock) {
+				c.Advance(9 * time.Second)
+			},
+			wantFire: false,
+		},
+		{
+			name: "advanced exactly",
+			d:    10 * time.Second,
+			advance: func(c *FakeClock) {
+				c.Advance(9 * time.Second)
+				c.Advance(1 * time.Second)
+			},
+			wantFire: true,
+			wantTime: fakeEpoch.Add(10 * time.Second),
+		},
+		{
+			name: "advanced past",
+			d:    10 * time.Second,
+			advance: func(c *FakeClock) {
+				c.Advance(1 * time.Minute)
+			},
+			wantFire: true,
+			wantTime: fakeEpoch.Add(1 * time.Minute),
+		},
+		{
+			name: "set past",
+			d:    10 * time.Second,
+			advance: func(c *FakeClock) {
+				c.Set(fakeEpoch.Add(1 * time.Hour))
+			},
+			wantFire: true,
+			wantTime: fakeEpoch.Add(1 * time.Hour),
+		},
+		{
+			name: "set backwards",
+			d:    10 * time.Second,
+			advance: func(c *FakeClock) {
+				c.Set(fakeEpoch.Add(-1 * time.Hour))
+			},
+			wantFire: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFakeClock(fakeEpoch)
+			ch := c.After(tt.d)
+
+			tt.advance(c)
+
+			got, fired := received(ch)
+			assert.Equal(t, tt.wantFire, fired)
+			assert.Equal(t, tt.wantTime, got)
+
+			// Channels only ever fire once.
+			c.Advance(24 * time.Hour)
+			_, fired = received(ch)
+			assert.Equal(t, !tt.wantFire, fired)
+		})
+	}
+}
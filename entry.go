@@ -0,0 +1,179 @@
+package mocktesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EntryKind is the kind of method which produced an Entry.
+type EntryKind string
+
+const (
+	// EntryLog is output produced by Log() or Logf().
+	EntryLog EntryKind = "log"
+
+	// EntryError is output produced by Error() or Errorf().
+	EntryError EntryKind = "error"
+
+	// EntryFatal is output produced by Fatal() or Fatalf(), or by methods like
+	// Setenv() and Chdir() failing the *T instance.
+	EntryFatal EntryKind = "fatal"
+
+	// EntrySkip is output produced by Skip() or Skipf().
+	EntrySkip EntryKind = "skip"
+)
+
+// Entry is a single piece of output recorded by a *T instance.
+type Entry struct {
+	// Seq is the sequence number of the entry. It is taken from the same
+	// sequence as Call.Seq, so entries and calls can be ordered relative to
+	// each other, even across *T instances.
+	Seq uint64
+
+	// Kind is the kind of method which produced the entry.
+	Kind EntryKind
+
+	// Format is the format string given to formatted methods like Logf(), and
+	// is empty for methods like Log().
+	Format string
+
+	// Args are the raw args given to the method, excluding any format string.
+	Args []interface{}
+
+	// Text is the rendered output, exactly as it is returned by Output().
+	Text string
+
+	// File and Line is the location that *testing.T would attribute the
+	// output to. This is the first caller which has not been marked as a
+	// helper function by calling Helper().
+	//
+	// File is the full path to the source file, while *testing.T only prints
+	// its base name.
+	File string
+	Line int
+}
+
+// lnEntry returns an Entry of the given kind, with args rendered to text with
+// fmt.Sprintln() like Log() does.
+func lnEntry(kind EntryKind, args []interface{}) Entry {
+	return Entry{Kind: kind, Args: args, Text: fmt.Sprintln(args...)}
+}
+
+// fEntry returns an Entry of the given kind, with format and args rendered to
+// text like Logf() does.
+func fEntry(kind EntryKind, format string, args []interface{}) Entry {
+	return Entry{
+		Kind:   kind,
+		Format: format,
+		Args:   args,
+		Text:   sprintf(format, args...),
+	}
+}
+
+// packageDir is the directory containing the source files of this package. It
+// is used to identify stack frames which belong to mocktesting itself.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Dir(file)
+}()
+
+// isInternalFrame returns true if the given frame is within the non-test source
+// files of this package.
+func isInternalFrame(frame runtime.Frame) bool {
+	return filepath.Dir(frame.File) == packageDir &&
+		!strings.HasSuffix(frame.File, "_test.go")
+}
+
+// isBoundaryFrame returns true if the given frame marks the start of the
+// goroutine from the perspective of the test, meaning no frames beyond it
+// should be considered when looking for the caller of a *T method.
+func isBoundaryFrame(frame runtime.Frame) bool {
+	return isInternalFrame(frame) ||
+		strings.HasPrefix(frame.Function, "runtime.") ||
+		strings.HasPrefix(frame.Function, "testing.")
+}
+
+// callerFrames returns the stack frames of the current goroutine which are
+// outside of mocktesting, starting with the caller of the exported *T method,
+// and ending with the outermost frame before the start of the goroutine, a
+// mocktesting function which started the goroutine, or a testing package
+// function, whichever comes first.
+func callerFrames() []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var r []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		if len(r) == 0 && isInternalFrame(frame) {
+			if !more {
+				return r
+			}
+
+			continue
+		}
+		if isBoundaryFrame(frame) {
+			return r
+		}
+
+		r = append(r, frame)
+		if !more {
+			return r
+		}
+	}
+}
+
+// attributedFrame returns the stack frame which *testing.T would attribute
+// output to. This is the first of the given frames which is not a function that
+// has called Helper(). If all frames are helpers, the outermost frame is used
+// instead.
+func attributedFrame(
+	frames []runtime.Frame,
+	helpers map[string]bool,
+) runtime.Frame {
+	if len(frames) == 0 {
+		return runtime.Frame{}
+	}
+
+	for _, frame := range frames {
+		if !helpers[frame.Function] {
+			return frame
+		}
+	}
+
+	return frames[len(frames)-1]
+}
+
+// missingHelpers returns the names of functions in the given frames which have
+// not called Helper(), excluding the outermost frame, as it is assumed to be
+// the test function itself.
+func missingHelpers(frames []runtime.Frame, helpers map[string]bool) []string {
+	if len(frames) < 2 {
+		return nil
+	}
+
+	var r []string
+	for _, frame := range frames[:len(frames)-1] {
+		if !helpers[frame.Function] {
+			r = append(r, frame.Function)
+		}
+	}
+
+	return r
+}
+
+func (t *T) helperSet() map[string]bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	r := make(map[string]bool, len(t.helpers))
+	for _, name := range t.helpers {
+		r[name] = true
+	}
+
+	return r
+}
@@ -0,0 +1,224 @@
+package mocktesting
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callerLine returns the line number of the line after the line it is called
+// from.
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+
+	return line + 1
+}
+
+func TestT_log_attribution(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	var line int
+	notHelper := func(t testing.TB) {
+		line = callerLine()
+		t.Error("from not helper")
+	}
+	helper := func(t testing.TB) {
+		t.Helper()
+		t.Error("from helper")
+	}
+	nestedHelper := func(t testing.TB) {
+		t.Helper()
+		helper(t)
+	}
+	helperCallingNotHelper := func(t testing.TB) {
+		t.Helper()
+		notHelper(t)
+	}
+
+	tests := []struct {
+		name string
+		f    func(mt *T) int
+	}{
+		{
+			name: "direct call",
+			f: func(mt *T) int {
+				l := callerLine()
+				mt.Log("direct")
+
+				return l
+			},
+		},
+		{
+			name: "not a helper",
+			f: func(mt *T) int {
+				notHelper(mt)
+
+				return line
+			},
+		},
+		{
+			name: "helper",
+			f: func(mt *T) int {
+				l := callerLine()
+				helper(mt)
+
+				return l
+			},
+		},
+		{
+			name: "nested helpers",
+			f: func(mt *T) int {
+				l := callerLine()
+				nestedHelper(mt)
+
+				return l
+			},
+		},
+		{
+			name: "helper calling not helper",
+			f: func(mt *T) int {
+				helperCallingNotHelper(mt)
+
+				return line
+			},
+		},
+		{
+			name: "all frames are helpers",
+			f: func(mt *T) int {
+				var l int
+				Go(func() {
+					mt.Helper()
+					l = callerLine()
+					helper(mt)
+				})
+
+				return l
+			},
+		},
+		{
+			name: "within sub-test",
+			f: func(mt *T) int {
+				var l int
+				mt.Run("sub", func(t testing.TB) {
+					l = callerLine()
+					helper(t)
+					mt.entries = t.(*T).entries
+				})
+
+				return l
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestAttribution")
+
+			wantLine := tt.f(mt)
+
+			got := mt.Entries()
+			require.Len(t, got, 1)
+			assert.Equal(t, testFile, got[0].File)
+			assert.Equal(t, wantLine, got[0].Line)
+		})
+	}
+}
+
+func Test_isInternalFrame(t *testing.T) {
+	pc, testFile, _, _ := runtime.Caller(0)
+	frame := runtime.Frame{
+		File:     testFile,
+		Function: runtime.FuncForPC(pc).Name(),
+	}
+
+	assert.False(t, isInternalFrame(frame))
+
+	mt := &T{}
+	var internal runtime.Frame
+	mt.Cleanup(func() {
+		pcs := make([]uintptr, 10)
+		n := runtime.Callers(2, pcs)
+		internal, _ = runtime.CallersFrames(pcs[:n]).Next()
+	})
+	mt.Finish()
+
+	assert.True(t, isInternalFrame(internal), internal.Function)
+}
+
+func TestT_log_missingHelpers(t *testing.T) {
+	funcName := func(f interface{}) string {
+		return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	}
+
+	badHelper := func(t testing.TB) {
+		t.Error("from bad helper")
+	}
+	goodHelper := func(t testing.TB) {
+		t.Helper()
+		badHelper(t)
+	}
+	otherBadHelper := func(t testing.TB) {
+		goodHelper(t)
+		badHelper(t)
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		f       func(t testing.TB)
+		want    []string
+	}{
+		{
+			name:    "direct call",
+			options: []Option{WithHelperCheck()},
+			f:       func(t testing.TB) { t.Log("hello") },
+			want:    nil,
+		},
+		{
+			name:    "good helper",
+			options: []Option{WithHelperCheck()},
+			f: func(t testing.TB) {
+				t.Helper()
+				t.Log("hello")
+			},
+			want: nil,
+		},
+		{
+			name:    "bad helper",
+			options: []Option{WithHelperCheck()},
+			f:       func(t testing.TB) { badHelper(t) },
+			want:    []string{funcName(badHelper)},
+		},
+		{
+			name:    "good helper calling bad helper",
+			options: []Option{WithHelperCheck()},
+			f:       func(t testing.TB) { goodHelper(t) },
+			want:    []string{funcName(badHelper)},
+		},
+		{
+			name:    "bad helpers calling each other",
+			options: []Option{WithHelperCheck()},
+			f:       func(t testing.TB) { otherBadHelper(t) },
+			want: []string{
+				funcName(badHelper),
+				funcName(otherBadHelper),
+			},
+		},
+		{
+			name: "without helper check",
+			f:    func(t testing.TB) { otherBadHelper(t) },
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestMissingHelpers", tt.options...)
+
+			mt.Run("sub", tt.f)
+
+			assert.Equal(t, tt.want, mt.Subtests()[0].MissingHelpers())
+		})
+	}
+}
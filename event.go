@@ -0,0 +1,71 @@
+package mocktesting
+
+import (
+	"math"
+	"sort"
+)
+
+// eventAction is the type of an event in the life of a *T instance.
+type eventAction int
+
+const (
+	eventRun eventAction = iota
+	eventOutput
+	eventPause
+	eventCont
+	eventEnd
+)
+
+// event is a single event in the life of a *T instance, used to render
+// transcripts of a tree of *T instances in the order things happened.
+type event struct {
+	seq    uint64
+	action eventAction
+	t      *T
+	entry  Entry
+}
+
+// events returns all events of the *T instance and its sub-tests, ordered by
+// sequence number.
+//
+// A *T instance which has not been finished with Finish() gets an end event
+// after all other events, with sub-tests ending before their parents.
+func (t *T) events() []event {
+	var r []event
+	t.collectEvents(&r)
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].seq < r[j].seq
+	})
+
+	return r
+}
+
+func (t *T) collectEvents(r *[]event) {
+	t.mux.RLock()
+	entries := t.entries
+	subtests := t.subtests
+	runSeq, pauseSeq, contSeq := t.runSeq, t.pauseSeq, t.contSeq
+	endSeq := t.endSeq
+	t.mux.RUnlock()
+
+	*r = append(*r, event{seq: runSeq, action: eventRun, t: t})
+	for _, e := range entries {
+		*r = append(*r, event{seq: e.Seq, action: eventOutput, t: t, entry: e})
+	}
+	if pauseSeq != 0 {
+		*r = append(*r, event{seq: pauseSeq, action: eventPause, t: t})
+	}
+	if contSeq != 0 {
+		*r = append(*r, event{seq: contSeq, action: eventCont, t: t})
+	}
+
+	for _, subtest := range subtests {
+		subtest.collectEvents(r)
+	}
+
+	if endSeq == 0 {
+		endSeq = math.MaxUint64
+	}
+	*r = append(*r, event{seq: endSeq, action: eventEnd, t: t})
+}
@@ -0,0 +1,58 @@
+package expect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{
+			name: "empty",
+			diff: "",
+		},
+		{
+			name: "equal",
+			want: "foo\nbar\n",
+			got:  "foo\nbar\n",
+			diff: "  foo\n  bar\n",
+		},
+		{
+			name: "added lines",
+			want: "foo\n",
+			got:  "foo\nbar\nbaz\n",
+			diff: "  foo\n+ bar\n+ baz\n",
+		},
+		{
+			name: "removed lines",
+			want: "foo\nbar\nbaz\n",
+			got:  "baz\n",
+			diff: "- foo\n- bar\n  baz\n",
+		},
+		{
+			name: "changed line",
+			want: "foo\nbar\nbaz\n",
+			got:  "foo\nqux\nbaz\n",
+			diff: "  foo\n- bar\n+ qux\n  baz\n",
+		},
+		{
+			name: "everything changed",
+			want: "foo\n",
+			got:  "bar",
+			diff: "- foo\n+ bar\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diff(tt.want, tt.got)
+
+			assert.Equal(t, tt.diff, got)
+		})
+	}
+}
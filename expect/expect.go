@@ -0,0 +1,301 @@
+// Package expect provides assertions for verifying the state of a
+// *mocktesting.T instance after a test helper has been run against it.
+//
+// Each assertion reports failures to the given testing.TB, usually the real
+// *testing.T of the test, and returns true if the assertion passed. Failure
+// messages include the output of the *mocktesting.T instance, making it easier
+// to see why a helper behaved the way it did.
+package expect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+)
+
+// Result is the final result of a test, as reported by "go test".
+type Result string
+
+// Results which a test can end with.
+const (
+	Pass Result = "PASS"
+	Fail Result = "FAIL"
+	Skip Result = "SKIP"
+)
+
+// abortMethods are the methods of a *mocktesting.T which abort the goroutine
+// they are called from.
+var abortMethods = map[string]bool{
+	"FailNow": true,
+	"Fatal":   true,
+	"Fatalf":  true,
+	"SkipNow": true,
+	"Skip":    true,
+	"Skipf":   true,
+}
+
+// Failed asserts that mt is marked as failed.
+func Failed(t testing.TB, mt *mocktesting.T) bool {
+	t.Helper()
+
+	if mt.FailedCount() == 0 {
+		return failf(t, mt,
+			"expected %s to have failed, but it did not", name(mt),
+		)
+	}
+
+	return true
+}
+
+// Passed asserts that mt has neither failed nor been skipped.
+func Passed(t testing.TB, mt *mocktesting.T) bool {
+	t.Helper()
+
+	if r := resultOf(mt); r != Pass {
+		return failf(t, mt,
+			"expected %s to have passed, but result is %s", name(mt), r,
+		)
+	}
+
+	return true
+}
+
+// Skipped asserts that mt is marked as skipped, and has not failed.
+func Skipped(t testing.TB, mt *mocktesting.T) bool {
+	t.Helper()
+
+	if resultOf(mt) != Skip {
+		return failf(t, mt,
+			"expected %s to have been skipped, but it was not", name(mt),
+		)
+	}
+
+	return true
+}
+
+// Aborted asserts that mt aborted the goroutine it was running on, via any of
+// FailNow(), Fatal(), Fatalf(), SkipNow(), Skip() or Skipf().
+func Aborted(t testing.TB, mt *mocktesting.T) bool {
+	t.Helper()
+
+	if !mt.Aborted() {
+		return failf(t, mt,
+			"expected %s to have aborted, but it did not", name(mt),
+		)
+	}
+
+	return true
+}
+
+// AbortedWith asserts that mt aborted the goroutine it was running on by a call
+// to the given method, for example "Fatalf" or "SkipNow".
+func AbortedWith(t testing.TB, mt *mocktesting.T, method string) bool {
+	t.Helper()
+
+	if !abortMethods[method] {
+		t.Errorf("expect: %q is not a method which aborts a test", method)
+
+		return false
+	}
+
+	if !mt.Aborted() {
+		return failf(t, mt,
+			"expected %s to have aborted with %s(), but it did not abort",
+			name(mt), method,
+		)
+	}
+
+	if got := abortedWith(mt); got != method {
+		return failf(t, mt,
+			"expected %s to have aborted with %s(), but it aborted with %s()",
+			name(mt), method, got,
+		)
+	}
+
+	return true
+}
+
+// OutputContains asserts that the output of mt contains substr.
+func OutputContains(t testing.TB, mt *mocktesting.T, substr string) bool {
+	t.Helper()
+
+	if !strings.Contains(output(mt), substr) {
+		return failf(t, mt,
+			"expected output of %s to contain:\n%s", name(mt), indent(substr),
+		)
+	}
+
+	return true
+}
+
+// OutputMatches asserts that the output of mt matches the regular expression
+// re, which may be a *regexp.Regexp or a string.
+func OutputMatches(t testing.TB, mt *mocktesting.T, re interface{}) bool {
+	t.Helper()
+
+	var rx *regexp.Regexp
+	switch v := re.(type) {
+	case *regexp.Regexp:
+		rx = v
+	case string:
+		var err error
+		rx, err = regexp.Compile(v)
+		if err != nil {
+			t.Errorf("expect: invalid regular expression: %s", err)
+
+			return false
+		}
+	default:
+		t.Errorf("expect: unsupported regular expression type %T", re)
+
+		return false
+	}
+
+	if !rx.MatchString(output(mt)) {
+		return failf(t, mt,
+			"expected output of %s to match: %s", name(mt), rx,
+		)
+	}
+
+	return true
+}
+
+// Output asserts that the output of mt is exactly want. On mismatch, a line by
+// line diff of want and the actual output is reported.
+func Output(t testing.TB, mt *mocktesting.T, want string) bool {
+	t.Helper()
+
+	if got := output(mt); got != want {
+		t.Errorf(
+			"output of %s differs from expected (-want +got):\n%s",
+			name(mt), diff(want, got),
+		)
+
+		return false
+	}
+
+	return true
+}
+
+// SubtestResult asserts that the sub-test of mt with the given name exists,
+// and that it ended with the given result. The name is relative to mt, and
+// may refer to nested sub-tests by separating names with "/". Like Run() does,
+// spaces in the name are replaced with underscores.
+func SubtestResult(
+	t testing.TB,
+	mt *mocktesting.T,
+	subtest string,
+	want Result,
+) bool {
+	t.Helper()
+
+	st := findSubtest(mt, subtest)
+	if st == nil {
+		var names []string
+		walk(mt, func(st *mocktesting.T) {
+			names = append(names, "    "+st.TestName())
+		})
+
+		msg := fmt.Sprintf("sub-test %q of %s not found", subtest, name(mt))
+		if len(names) > 0 {
+			msg += ", available sub-tests:\n" + strings.Join(names, "\n")
+		}
+		t.Error(msg)
+
+		return false
+	}
+
+	if got := resultOf(st); got != want {
+		return failf(t, st,
+			"expected sub-test %s to have result %s, but got %s",
+			name(st), want, got,
+		)
+	}
+
+	return true
+}
+
+// failf reports a failure to t, followed by the output of mt, and returns
+// false.
+func failf(
+	t testing.TB,
+	mt *mocktesting.T,
+	format string,
+	args ...interface{},
+) bool {
+	t.Helper()
+
+	msg := fmt.Sprintf(format, args...)
+	if out := output(mt); out != "" {
+		msg += "\noutput:\n" + indent(out)
+	} else {
+		msg += "\noutput: (none)"
+	}
+	t.Error(msg)
+
+	return false
+}
+
+func abortedWith(mt *mocktesting.T) string {
+	calls := mt.Calls()
+	for i := len(calls) - 1; i >= 0; i-- {
+		if abortMethods[calls[i].Method] {
+			return calls[i].Method
+		}
+	}
+
+	return ""
+}
+
+func resultOf(mt *mocktesting.T) Result {
+	return Result(mt.Result())
+}
+
+func findSubtest(mt *mocktesting.T, subtest string) *mocktesting.T {
+	fullname := strings.ReplaceAll(subtest, " ", "_")
+	if mt.TestName() != "" {
+		fullname = mt.TestName() + "/" + fullname
+	}
+
+	var found *mocktesting.T
+	walk(mt, func(st *mocktesting.T) {
+		if found == nil && st.TestName() == fullname {
+			found = st
+		}
+	})
+
+	return found
+}
+
+// walk calls f for each sub-test of mt, recursively.
+func walk(mt *mocktesting.T, f func(*mocktesting.T)) {
+	for _, st := range mt.Subtests() {
+		f(st)
+		walk(st, f)
+	}
+}
+
+func output(mt *mocktesting.T) string {
+	return strings.Join(mt.Output(), "")
+}
+
+func name(mt *mocktesting.T) string {
+	if mt.TestName() == "" {
+		return "mock test"
+	}
+
+	return fmt.Sprintf("%q", mt.TestName())
+}
+
+// indent indents each line of s by four spaces.
+func indent(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = "    " + l
+	}
+
+	return strings.Join(lines, "\n")
+}
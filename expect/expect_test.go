@@ -0,0 +1,408 @@
+package expect
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run returns a finished *mocktesting.T named "TestFoo" which has been run
+// with the given function.
+func run(f func(t testing.TB)) *mocktesting.T {
+	mt := mocktesting.NewT("TestFoo")
+	mocktesting.Go(func() {
+		f(mt)
+	})
+	mt.Finish()
+
+	return mt
+}
+
+func pass(t testing.TB) {
+	t.Log("all good")
+}
+
+func fail(t testing.TB) {
+	t.Error("oops")
+}
+
+func fatal(t testing.TB) {
+	t.Fatalf("oops: %d", 42)
+}
+
+func failNow(t testing.TB) {
+	t.FailNow()
+}
+
+func skip(t testing.TB) {
+	t.Skip("not today")
+}
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		name   string
+		f      func(t testing.TB)
+		assert func(t testing.TB, mt *mocktesting.T) bool
+		want   []string
+	}{
+		{
+			name: "Failed on failed test",
+			f:    fail,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Failed(t, mt)
+			},
+		},
+		{
+			name: "Failed on passed test",
+			f:    pass,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Failed(t, mt)
+			},
+			want: []string{
+				`expected "TestFoo" to have failed, but it did not`,
+				"output:\n    all good\n",
+			},
+		},
+		{
+			name: "Passed on passed test",
+			f:    pass,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Passed(t, mt)
+			},
+		},
+		{
+			name: "Passed on failed test",
+			f:    fail,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Passed(t, mt)
+			},
+			want: []string{
+				`expected "TestFoo" to have passed, but result is FAIL`,
+				"output:\n    oops\n",
+			},
+		},
+		{
+			name: "Passed on skipped test",
+			f:    skip,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Passed(t, mt)
+			},
+			want: []string{
+				`expected "TestFoo" to have passed, but result is SKIP`,
+				"output:\n    not today\n",
+			},
+		},
+		{
+			name: "Skipped on skipped test",
+			f:    skip,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Skipped(t, mt)
+			},
+		},
+		{
+			name: "Skipped on passed test",
+			f:    func(testing.TB) {},
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Skipped(t, mt)
+			},
+			want: []string{
+				`expected "TestFoo" to have been skipped, but it was not`,
+				"output: (none)",
+			},
+		},
+		{
+			name: "Aborted on aborted test",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Aborted(t, mt)
+			},
+		},
+		{
+			name: "Aborted on failed test",
+			f:    fail,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Aborted(t, mt)
+			},
+			want: []string{
+				`expected "TestFoo" to have aborted, but it did not`,
+			},
+		},
+		{
+			name: "AbortedWith matching method",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "Fatalf")
+			},
+		},
+		{
+			name: "AbortedWith FailNow",
+			f:    failNow,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "FailNow")
+			},
+		},
+		{
+			name: "AbortedWith Skip",
+			f:    skip,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "Skip")
+			},
+		},
+		{
+			name: "AbortedWith other method",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "FailNow")
+			},
+			want: []string{
+				`expected "TestFoo" to have aborted with FailNow(), ` +
+					`but it aborted with Fatalf()`,
+				"output:\n    oops: 42\n",
+			},
+		},
+		{
+			name: "AbortedWith on test which did not abort",
+			f:    fail,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "Fatal")
+			},
+			want: []string{
+				`expected "TestFoo" to have aborted with Fatal(), ` +
+					`but it did not abort`,
+			},
+		},
+		{
+			name: "AbortedWith non-aborting method",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return AbortedWith(t, mt, "Error")
+			},
+			want: []string{
+				`expect: "Error" is not a method which aborts a test`,
+			},
+		},
+		{
+			name: "OutputContains match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputContains(t, mt, "oops: 42")
+			},
+		},
+		{
+			name: "OutputContains no match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputContains(t, mt, "oops: 43")
+			},
+			want: []string{
+				"expected output of \"TestFoo\" to contain:\n    oops: 43\n",
+				"output:\n    oops: 42\n",
+			},
+		},
+		{
+			name: "OutputMatches string match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputMatches(t, mt, `oops: \d+`)
+			},
+		},
+		{
+			name: "OutputMatches regexp match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputMatches(t, mt, regexp.MustCompile(`^oops`))
+			},
+		},
+		{
+			name: "OutputMatches no match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputMatches(t, mt, `oops: \D+`)
+			},
+			want: []string{
+				`expected output of "TestFoo" to match: oops: \D+`,
+			},
+		},
+		{
+			name: "OutputMatches invalid regexp",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputMatches(t, mt, `oops: (`)
+			},
+			want: []string{"expect: invalid regular expression: "},
+		},
+		{
+			name: "OutputMatches unsupported type",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return OutputMatches(t, mt, 42)
+			},
+			want: []string{"expect: unsupported regular expression type int"},
+		},
+		{
+			name: "Output match",
+			f:    fatal,
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Output(t, mt, "oops: 42\n")
+			},
+		},
+		{
+			name: "Output mismatch",
+			f: func(t testing.TB) {
+				t.Log("foo\nbar\nbaz")
+			},
+			assert: func(t testing.TB, mt *mocktesting.T) bool {
+				return Output(t, mt, "foo\nqux\nbaz\n")
+			},
+			want: []string{
+				`output of "TestFoo" differs from expected (-want +got):`,
+				"  foo\n- qux\n+ bar\n  baz\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := run(tt.f)
+			reporter := mocktesting.NewT("reporter")
+			calls := mt.Calls()
+
+			got := tt.assert(reporter, mt)
+
+			assert.Equal(t, calls, mt.Calls(), "assertion recorded calls")
+
+			assert.Equal(t, len(tt.want) == 0, got)
+			assert.Equal(t, len(tt.want) != 0, reporter.Failed())
+			out := strings.Join(reporter.Output(), "")
+			for _, want := range tt.want {
+				assert.Contains(t, out, want)
+			}
+			if len(tt.want) == 0 {
+				assert.Empty(t, out)
+			}
+		})
+	}
+}
+
+// recordedCalls returns the calls recorded by mt and all of its sub-tests.
+func recordedCalls(mt *mocktesting.T) map[*mocktesting.T][]mocktesting.Call {
+	r := map[*mocktesting.T][]mocktesting.Call{mt: mt.Calls()}
+	walk(mt, func(st *mocktesting.T) {
+		r[st] = st.Calls()
+	})
+
+	return r
+}
+
+func TestAssertions_expectations(t *testing.T) {
+	reporter := mocktesting.NewT("reporter")
+	mt := mocktesting.NewT("TestFoo", mocktesting.WithTestingT(reporter))
+	for _, m := range []string{"Name", "Failed", "Skipped"} {
+		mt.Expect().Call(m).Never()
+	}
+	mt.Run("sub", fail)
+	mt.Finish()
+
+	Failed(t, mt)
+	Passed(reporter, mt)
+	Skipped(reporter, mt)
+	SubtestResult(t, mt, "sub", Fail)
+
+	assert.Empty(t, mt.Expect().Unexpected())
+	assert.Empty(t, mt.CallsTo("Name"))
+}
+
+func TestSubtestResult(t *testing.T) {
+	mt := mocktesting.NewT("TestFoo")
+	mt.Run("pass", pass)
+	mt.Run("fail", fail)
+	mt.Run("nested", func(t testing.TB) {
+		t.(*mocktesting.T).Run("skip", skip)
+	})
+	mt.Run("with spaces", func(t testing.TB) {
+		t.(*mocktesting.T).Run("and more spaces", fail)
+	})
+	mt.Finish()
+
+	tests := []struct {
+		name    string
+		subtest string
+		result  Result
+		want    []string
+	}{
+		{name: "pass", subtest: "pass", result: Pass},
+		{name: "fail", subtest: "fail", result: Fail},
+		{name: "nested", subtest: "nested", result: Pass},
+		{name: "nested skip", subtest: "nested/skip", result: Skip},
+		{name: "spaces", subtest: "with spaces", result: Fail},
+		{
+			name:    "nested spaces",
+			subtest: "with spaces/and more spaces",
+			result:  Fail,
+		},
+		{
+			name:    "underscores",
+			subtest: "with_spaces/and_more_spaces",
+			result:  Fail,
+		},
+		{
+			name:    "wrong result",
+			subtest: "fail",
+			result:  Pass,
+			want: []string{
+				`expected sub-test "TestFoo/fail" to have result PASS, ` +
+					"but got FAIL",
+				"output:\n    oops\n",
+			},
+		},
+		{
+			name:    "not found",
+			subtest: "nope",
+			result:  Pass,
+			want: []string{
+				`sub-test "nope" of "TestFoo" not found, ` +
+					"available sub-tests:\n" +
+					"    TestFoo/pass\n" +
+					"    TestFoo/fail\n" +
+					"    TestFoo/nested\n" +
+					"    TestFoo/nested/skip\n" +
+					"    TestFoo/with_spaces\n" +
+					"    TestFoo/with_spaces/and_more_spaces\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := mocktesting.NewT("reporter")
+			calls := recordedCalls(mt)
+
+			got := SubtestResult(reporter, mt, tt.subtest, tt.result)
+
+			assert.Equal(t,
+				calls, recordedCalls(mt), "assertion recorded calls",
+			)
+
+			assert.Equal(t, len(tt.want) == 0, got)
+			out := strings.Join(reporter.Output(), "")
+			for _, want := range tt.want {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+}
+
+func TestSubtestResult_noSubtests(t *testing.T) {
+	mt := run(pass)
+	reporter := mocktesting.NewT("reporter")
+
+	got := SubtestResult(reporter, mt, "foo", Pass)
+
+	assert.False(t, got)
+	require.Len(t, reporter.Output(), 1)
+	assert.Contains(t,
+		reporter.Output()[0], `sub-test "foo" of "TestFoo" not found`+"\n",
+	)
+}
@@ -0,0 +1,113 @@
+//go:build go1.18
+// +build go1.18
+
+package expect
+
+import (
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+)
+
+// Outcome is the expected outcome of running a test helper against a
+// *mocktesting.T instance.
+type Outcome string
+
+// Outcomes which a Case can expect.
+const (
+	// OutcomePass expects the helper to neither fail nor skip.
+	OutcomePass Outcome = "pass"
+
+	// OutcomeFail expects the helper to fail without aborting, like when
+	// Error() or Fail() is called.
+	OutcomeFail Outcome = "fail"
+
+	// OutcomeFatal expects the helper to fail and abort, like when Fatal() or
+	// FailNow() is called.
+	OutcomeFatal Outcome = "fatal"
+
+	// OutcomeSkip expects the helper to skip the test.
+	OutcomeSkip Outcome = "skip"
+)
+
+// Case is a single case of a table run by Table.
+type Case[A any] struct {
+	// Name is the name of the case, used as the name of the real sub-test the
+	// case is run in.
+	Name string
+
+	// Args is given to the helper when the case is run.
+	Args A
+
+	// Want is the expected outcome of running the helper.
+	Want Outcome
+
+	// Output is an optional regular expression which the output of the
+	// *mocktesting.T instance is expected to match.
+	Output string
+}
+
+// Table runs helper once for each of the given cases, each within its own
+// sub-test of t. Each case gets a new *mocktesting.T instance created with the
+// given options, which helper is run against in a separate goroutine via
+// mocktesting.Go(). Any mismatch between the expectations of a case and the
+// resulting state of the *mocktesting.T instance is reported to the sub-test.
+func Table[A any](
+	t *testing.T,
+	helper func(testing.TB, A),
+	cases []Case[A],
+	options ...mocktesting.Option,
+) {
+	t.Helper()
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Helper()
+			runCase(t, helper, c, options...)
+		})
+	}
+}
+
+// runCase runs a single case of a table, reporting mismatches to t, and returns
+// the *mocktesting.T instance the case was run against.
+func runCase[A any](
+	t testing.TB,
+	helper func(testing.TB, A),
+	c Case[A],
+	options ...mocktesting.Option,
+) *mocktesting.T {
+	t.Helper()
+
+	mt := mocktesting.NewT(t.Name(), options...)
+	mocktesting.Go(func() {
+		helper(mt, c.Args)
+	})
+	mt.Finish()
+
+	switch c.Want {
+	case OutcomePass:
+		Passed(t, mt)
+	case OutcomeFail:
+		if Failed(t, mt) && mt.Aborted() {
+			failf(t, mt,
+				"expected %s to have failed without aborting, but it aborted",
+				name(mt),
+			)
+		}
+	case OutcomeFatal:
+		if Failed(t, mt) {
+			Aborted(t, mt)
+		}
+	case OutcomeSkip:
+		Skipped(t, mt)
+	default:
+		t.Errorf("expect: unknown outcome %q", c.Want)
+	}
+
+	if c.Output != "" {
+		OutputMatches(t, mt, c.Output)
+	}
+
+	return mt
+}
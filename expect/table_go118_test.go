@@ -0,0 +1,139 @@
+//go:build go1.18
+// +build go1.18
+
+package expect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkPositive(t testing.TB, n int) {
+	t.Helper()
+
+	switch {
+	case n < 0:
+		t.Fatalf("%d is negative", n)
+	case n == 0:
+		t.Skip("zero is neither positive nor negative")
+	case n > 100:
+		t.Errorf("%d is too large", n)
+	}
+}
+
+func TestTable(t *testing.T) {
+	Table(t, checkPositive, []Case[int]{
+		{Name: "positive", Args: 1, Want: OutcomePass},
+		{
+			Name:   "negative",
+			Args:   -1,
+			Want:   OutcomeFatal,
+			Output: `^-1 is negative\n$`,
+		},
+		{Name: "zero", Args: 0, Want: OutcomeSkip, Output: "neither"},
+		{Name: "large", Args: 101, Want: OutcomeFail, Output: "too large"},
+	})
+}
+
+func TestTable_options(t *testing.T) {
+	Table(t, func(t testing.TB, _ struct{}) {
+		t.FailNow()
+		t.Log("still running")
+	}, []Case[struct{}]{
+		{Name: "no abort", Want: OutcomeFatal, Output: "still running"},
+	}, mocktesting.WithNoAbort())
+}
+
+func Test_runCase(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Case[int]
+		want []string
+	}{
+		{
+			name: "pass",
+			c:    Case[int]{Args: 1, Want: OutcomePass},
+		},
+		{
+			name: "pass mismatch",
+			c:    Case[int]{Args: -1, Want: OutcomePass},
+			want: []string{
+				`expected "reporter" to have passed, but result is FAIL`,
+			},
+		},
+		{
+			name: "fail",
+			c:    Case[int]{Args: 101, Want: OutcomeFail},
+		},
+		{
+			name: "fail with fatal",
+			c:    Case[int]{Args: -1, Want: OutcomeFail},
+			want: []string{
+				`expected "reporter" to have failed without aborting, ` +
+					"but it aborted",
+			},
+		},
+		{
+			name: "fail with pass",
+			c:    Case[int]{Args: 1, Want: OutcomeFail},
+			want: []string{
+				`expected "reporter" to have failed, but it did not`,
+			},
+		},
+		{
+			name: "fatal",
+			c:    Case[int]{Args: -1, Want: OutcomeFatal},
+		},
+		{
+			name: "fatal with fail",
+			c:    Case[int]{Args: 101, Want: OutcomeFatal},
+			want: []string{
+				`expected "reporter" to have aborted, but it did not`,
+			},
+		},
+		{
+			name: "skip",
+			c:    Case[int]{Args: 0, Want: OutcomeSkip},
+		},
+		{
+			name: "skip with fatal",
+			c:    Case[int]{Args: -1, Want: OutcomeSkip},
+			want: []string{
+				`expected "reporter" to have been skipped, but it was not`,
+			},
+		},
+		{
+			name: "output match",
+			c:    Case[int]{Args: -1, Want: OutcomeFatal, Output: "negative"},
+		},
+		{
+			name: "output mismatch",
+			c:    Case[int]{Args: -1, Want: OutcomeFatal, Output: "positive"},
+			want: []string{
+				`expected output of "reporter" to match: positive`,
+			},
+		},
+		{
+			name: "unknown outcome",
+			c:    Case[int]{Args: 1, Want: "explode"},
+			want: []string{`expect: unknown outcome "explode"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := mocktesting.NewT("reporter")
+
+			mt := runCase(reporter, checkPositive, tt.c)
+
+			assert.Equal(t, "reporter", mt.Name())
+			assert.Equal(t, len(tt.want) != 0, reporter.Failed())
+			out := strings.Join(reporter.Output(), "")
+			for _, want := range tt.want {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,440 @@
+package mocktesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Expectations holds the calls which a *T instance is expected to receive, as
+// declared with Call(). See Expect() for details.
+type Expectations struct {
+	t          *T
+	mux        sync.Mutex
+	calls      []*ExpectedCall
+	unexpected []Call
+	verified   bool
+}
+
+// ExpectedCall describes calls to a single method which a *T instance is
+// expected to receive. It is created by Expectations.Call(), and by default
+// expects exactly one call to the method, with any args.
+type ExpectedCall struct {
+	exp      *Expectations
+	method   string
+	args     []interface{}
+	hasArgs  bool
+	message  *regexp.Regexp
+	min, max int
+	count    int
+
+	// minSet and maxSet record whether the bounds have been set explicitly,
+	// so MinTimes() and MaxTimes() only replace the default of the other.
+	minSet, maxSet bool
+}
+
+// Expect returns the Expectations of the *T instance, used to declare up front
+// which calls the *T instance is expected to receive. For example:
+//
+//	mt := mocktesting.NewT("TestFoo", mocktesting.WithTestingT(t))
+//	mt.Expect().Call("Helper").MinTimes(1)
+//	mt.Expect().Call("Errorf").WithMessageMatching(`^bad value`)
+//	mt.Expect().Call("FailNow").Never()
+//
+// Once an expectation has been declared for a method, every call to that
+// method must match an expectation which has not yet been called as many
+// times as it allows. Calls which do not are reported as unexpected
+// immediately. Methods without any expectations may be called freely.
+//
+// Like *testing.T documents them, Error() and Errorf() also count as a call to
+// Fail(), Fatal() and Fatalf() as a call to FailNow(), and Skip() and Skipf()
+// as a call to SkipNow(), so the FailNow expectation above also catches calls
+// to Fatal().
+//
+// When Finish() is called, expectations which have not been called as many
+// times as they require are reported as missing.
+//
+// Both kinds of problems are reported via the TestingT given with the
+// WithTestingT() option, using its Errorf() method if it has one, and
+// otherwise Fatal(). If the option is not used, they cause a panic.
+func (t *T) Expect() *Expectations {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.expectations == nil {
+		t.expectations = &Expectations{t: t}
+	}
+
+	return t.expectations
+}
+
+// Call declares an expectation for calls to the given method, which by default
+// expects exactly one call with any args.
+func (e *Expectations) Call(method string) *ExpectedCall {
+	ec := &ExpectedCall{exp: e, method: method, min: 1, max: 1}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.calls = append(e.calls, ec)
+
+	return ec
+}
+
+// Unexpected returns all calls which did not match any expectation declared
+// for their method, or for the method they imply.
+func (e *Expectations) Unexpected() []Call {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.unexpected
+}
+
+// Satisfied returns true if there have been no unexpected calls, and every
+// expectation has been called at least as many times as it requires.
+func (e *Expectations) Satisfied() bool {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if len(e.unexpected) > 0 {
+		return false
+	}
+
+	for _, ec := range e.calls {
+		if ec.count < ec.min {
+			return false
+		}
+	}
+
+	return true
+}
+
+// impliedMethods maps methods to the method they are documented by
+// *testing.T as being equivalent to calling after Log().
+var impliedMethods = map[string]string{
+	"Error":  "Fail",
+	"Errorf": "Fail",
+	"Fatal":  "FailNow",
+	"Fatalf": "FailNow",
+	"Skip":   "SkipNow",
+	"Skipf":  "SkipNow",
+}
+
+// check matches c against the declared expectations, reporting it as an
+// unexpected call if there are expectations for its method, but none of them
+// accept it.
+//
+// Calls to methods like Fatal() are also matched against expectations for the
+// method they imply, like FailNow(), as if that method had been called without
+// args.
+func (e *Expectations) check(c Call) {
+	var unexpected []string
+	if !e.accept(c) {
+		unexpected = append(unexpected, describeCall(c))
+	}
+
+	if method, ok := impliedMethods[c.Method]; ok {
+		implied := c
+		implied.Method = method
+		implied.Args = nil
+		if !e.accept(implied) {
+			unexpected = append(unexpected, fmt.Sprintf(
+				"%s via %s", describeCall(implied), describeCall(c),
+			))
+		}
+	}
+
+	if len(unexpected) == 0 {
+		return
+	}
+
+	e.mux.Lock()
+	e.unexpected = append(e.unexpected, c)
+	e.mux.Unlock()
+
+	for _, desc := range unexpected {
+		e.t.reportf(
+			"unexpected call to %s at %s:%d",
+			desc, filepath.Base(c.File), c.Line,
+		)
+	}
+}
+
+// accept counts c against the first matching expectation which has not yet
+// been called as many times as it allows. It returns false if there are
+// expectations for the method of c, but none of them accept it.
+func (e *Expectations) accept(c Call) bool {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	expected := false
+	for _, ec := range e.calls {
+		if ec.method != c.Method {
+			continue
+		}
+
+		expected = true
+		if ec.matches(c) && (ec.max < 0 || ec.count < ec.max) {
+			ec.count++
+
+			return true
+		}
+	}
+
+	return !expected
+}
+
+// verify reports all expectations which have not been called as many times as
+// they require. Expectations are only verified once.
+func (e *Expectations) verify() {
+	e.mux.Lock()
+	if e.verified {
+		e.mux.Unlock()
+
+		return
+	}
+	e.verified = true
+
+	var missing []string
+	for _, ec := range e.calls {
+		if ec.count < ec.min {
+			missing = append(missing, fmt.Sprintf(
+				"missing call to %s: expected %s, got %d",
+				ec, ec.times(), ec.count,
+			))
+		}
+	}
+	e.mux.Unlock()
+
+	for _, msg := range missing {
+		e.t.reportf("%s", msg)
+	}
+}
+
+// WithArgs restricts the expectation to calls with exactly the given args,
+// compared with reflect.DeepEqual(). For methods which accept a format
+// string, like Errorf(), the format string is the first arg.
+func (ec *ExpectedCall) WithArgs(args ...interface{}) *ExpectedCall {
+	matchers := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		arg := arg
+		matchers = append(matchers, func(v interface{}) bool {
+			return reflect.DeepEqual(arg, v)
+		})
+	}
+
+	return ec.WithArgsMatching(matchers...)
+}
+
+// WithArgsMatching restricts the expectation to calls with as many args as
+// there are matchers, where each arg matches the matcher at the same position.
+// For methods which accept a format string, like Errorf(), the format string
+// is the first arg.
+//
+// A matcher can be a *regexp.Regexp or a string holding a regular expression,
+// which is matched against the arg formatted with fmt.Sprint(), or a
+// func(interface{}) bool. It panics if given any other type, or an invalid
+// regular expression.
+func (ec *ExpectedCall) WithArgsMatching(
+	matchers ...interface{},
+) *ExpectedCall {
+	args := make([]interface{}, 0, len(matchers))
+	for _, m := range matchers {
+		switch v := m.(type) {
+		case string:
+			args = append(args, regexp.MustCompile(v))
+		case *regexp.Regexp, func(interface{}) bool:
+			args = append(args, v)
+		default:
+			panic(fmt.Sprintf(
+				"mocktesting: unsupported arg matcher type %T", m,
+			))
+		}
+	}
+
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	ec.args = args
+	ec.hasArgs = true
+
+	return ec
+}
+
+// WithMessageMatching restricts the expectation to calls where the message
+// rendered from the args, like Log() and Logf() would render it, matches the
+// given regular expression. It panics if the regular expression is invalid.
+func (ec *ExpectedCall) WithMessageMatching(pattern string) *ExpectedCall {
+	re := regexp.MustCompile(pattern)
+
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	ec.message = re
+
+	return ec
+}
+
+// Times sets the exact number of times the method is expected to be called.
+func (ec *ExpectedCall) Times(n int) *ExpectedCall {
+	return ec.setTimes(n, n)
+}
+
+// MinTimes sets the minimum number of times the method is expected to be
+// called. If MaxTimes() has not been used, any number of calls above the
+// minimum are also accepted.
+func (ec *ExpectedCall) MinTimes(n int) *ExpectedCall {
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	ec.min = n
+	ec.minSet = true
+	if !ec.maxSet {
+		ec.max = -1
+	}
+
+	return ec
+}
+
+// MaxTimes sets the maximum number of times the method is expected to be
+// called. If MinTimes() has not been used, zero calls are also accepted.
+func (ec *ExpectedCall) MaxTimes(n int) *ExpectedCall {
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	ec.max = n
+	ec.maxSet = true
+	if !ec.minSet {
+		ec.min = 0
+	}
+
+	return ec
+}
+
+// AnyTimes accepts any number of calls, including zero.
+func (ec *ExpectedCall) AnyTimes() *ExpectedCall {
+	return ec.setTimes(0, -1)
+}
+
+// Never expects the method to not be called at all.
+func (ec *ExpectedCall) Never() *ExpectedCall {
+	return ec.setTimes(0, 0)
+}
+
+// Count returns the number of calls which have matched the expectation.
+func (ec *ExpectedCall) Count() int {
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	return ec.count
+}
+
+// String returns a description of the expectation, like:
+//
+//	Errorf(args matching ["^bad"]) with message matching "value"
+func (ec *ExpectedCall) String() string {
+	var b strings.Builder
+	b.WriteString(ec.method)
+
+	if ec.hasArgs {
+		matchers := make([]string, 0, len(ec.args))
+		for _, m := range ec.args {
+			if re, ok := m.(*regexp.Regexp); ok {
+				matchers = append(matchers, fmt.Sprintf("%q", re))
+			} else {
+				matchers = append(matchers, fmt.Sprintf("%T", m))
+			}
+		}
+		fmt.Fprintf(&b, "(args matching [%s])", strings.Join(matchers, ", "))
+	}
+
+	if ec.message != nil {
+		fmt.Fprintf(&b, " with message matching %q", ec.message)
+	}
+
+	return b.String()
+}
+
+func (ec *ExpectedCall) setTimes(min, max int) *ExpectedCall {
+	ec.exp.mux.Lock()
+	defer ec.exp.mux.Unlock()
+
+	ec.min = min
+	ec.max = max
+	ec.minSet = true
+	ec.maxSet = true
+
+	return ec
+}
+
+func (ec *ExpectedCall) times() string {
+	switch {
+	case ec.max < 0:
+		return fmt.Sprintf("at least %d", ec.min)
+	case ec.min == ec.max:
+		return fmt.Sprintf("exactly %d", ec.min)
+	default:
+		return fmt.Sprintf("between %d and %d", ec.min, ec.max)
+	}
+}
+
+func (ec *ExpectedCall) matches(c Call) bool {
+	if ec.hasArgs {
+		if len(c.Args) != len(ec.args) {
+			return false
+		}
+
+		for i, m := range ec.args {
+			switch v := m.(type) {
+			case *regexp.Regexp:
+				if !v.MatchString(fmt.Sprint(c.Args[i])) {
+					return false
+				}
+			case func(interface{}) bool:
+				if !v(c.Args[i]) {
+					return false
+				}
+			}
+		}
+	}
+
+	if ec.message != nil && !ec.message.MatchString(callMessage(c)) {
+		return false
+	}
+
+	return true
+}
+
+// formatMethods are the methods which accept a format string as their first
+// arg.
+var formatMethods = map[string]bool{
+	"Logf":   true,
+	"Errorf": true,
+	"Fatalf": true,
+	"Skipf":  true,
+}
+
+// callMessage returns the message rendered from the args of c, without a
+// trailing newline.
+func callMessage(c Call) string {
+	if formatMethods[c.Method] && len(c.Args) > 0 {
+		if format, ok := c.Args[0].(string); ok {
+			return strings.TrimSuffix(sprintf(format, c.Args[1:]...), "\n")
+		}
+	}
+
+	return strings.TrimSuffix(fmt.Sprintln(c.Args...), "\n")
+}
+
+// describeCall returns c formatted like a method call in Go source.
+func describeCall(c Call) string {
+	args := make([]string, 0, len(c.Args))
+	for _, arg := range c.Args {
+		args = append(args, fmt.Sprintf("%#v", arg))
+	}
+
+	return c.Method + "(" + strings.Join(args, ", ") + ")"
+}
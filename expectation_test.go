@@ -0,0 +1,504 @@
+package mocktesting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fatalOnlyT struct {
+	fatals []string
+}
+
+func (f *fatalOnlyT) Fatal(args ...interface{}) {
+	f.fatals = append(f.fatals, fmt.Sprint(args...))
+}
+
+func TestT_Expect(t *testing.T) {
+	tests := []struct {
+		name      string
+		expect    func(e *Expectations)
+		f         func(t testing.TB)
+		want      []string
+		satisfied bool
+	}{
+		{
+			name:      "no expectations",
+			expect:    func(e *Expectations) {},
+			f:         func(t testing.TB) { t.Error("foo") },
+			satisfied: true,
+		},
+		{
+			name: "single expected call",
+			expect: func(e *Expectations) {
+				e.Call("Helper")
+			},
+			f:         func(t testing.TB) { t.Helper() },
+			satisfied: true,
+		},
+		{
+			name: "missing call",
+			expect: func(e *Expectations) {
+				e.Call("Helper")
+			},
+			f: func(t testing.TB) {},
+			want: []string{
+				"mocktesting: missing call to Helper: expected exactly 1, " +
+					"got 0",
+			},
+		},
+		{
+			name: "too many calls",
+			expect: func(e *Expectations) {
+				e.Call("Helper")
+			},
+			f: func(t testing.TB) {
+				t.Helper()
+				t.Helper()
+			},
+			want: []string{
+				"mocktesting: unexpected call to Helper() at " +
+					"expectation_test.go:",
+			},
+		},
+		{
+			name: "calls to other methods are not checked",
+			expect: func(e *Expectations) {
+				e.Call("Helper")
+			},
+			f: func(t testing.TB) {
+				t.Helper()
+				t.Log("foo")
+				t.Error("bar")
+			},
+			satisfied: true,
+		},
+		{
+			name: "Times",
+			expect: func(e *Expectations) {
+				e.Call("Log").Times(3)
+			},
+			f: func(t testing.TB) {
+				t.Log("foo")
+				t.Log("bar")
+			},
+			want: []string{
+				"mocktesting: missing call to Log: expected exactly 3, got 2",
+			},
+		},
+		{
+			name: "Never",
+			expect: func(e *Expectations) {
+				e.Call("FailNow").Never()
+			},
+			f: func(t testing.TB) {
+				t.Error("foo")
+				t.FailNow()
+			},
+			want: []string{
+				"mocktesting: unexpected call to FailNow() at " +
+					"expectation_test.go:",
+			},
+		},
+		{
+			name: "Never with implied call",
+			expect: func(e *Expectations) {
+				e.Call("FailNow").Never()
+			},
+			f: func(t testing.TB) { t.Fatal("foo") },
+			want: []string{
+				"mocktesting: unexpected call to FailNow() via " +
+					"Fatal(\"foo\") at expectation_test.go:",
+			},
+		},
+		{
+			name: "implied calls",
+			expect: func(e *Expectations) {
+				e.Call("Fail").Times(2)
+				e.Call("SkipNow")
+				e.Call("Skipf").WithArgs("skip %d", 1)
+			},
+			f: func(t testing.TB) {
+				t.Error("foo")
+				t.Errorf("bar %d", 1)
+				t.Skipf("skip %d", 1)
+			},
+			satisfied: true,
+		},
+		{
+			name: "Never without calls",
+			expect: func(e *Expectations) {
+				e.Call("FailNow").Never()
+			},
+			f:         func(t testing.TB) { t.Error("foo") },
+			satisfied: true,
+		},
+		{
+			name: "MinTimes",
+			expect: func(e *Expectations) {
+				e.Call("Helper").MinTimes(2)
+			},
+			f: func(t testing.TB) {
+				t.Helper()
+				t.Helper()
+				t.Helper()
+			},
+			satisfied: true,
+		},
+		{
+			name: "MinTimes missing",
+			expect: func(e *Expectations) {
+				e.Call("Helper").MinTimes(2)
+			},
+			f: func(t testing.TB) { t.Helper() },
+			want: []string{
+				"mocktesting: missing call to Helper: expected at least 2, " +
+					"got 1",
+			},
+		},
+		{
+			name: "MaxTimes",
+			expect: func(e *Expectations) {
+				e.Call("Helper").MaxTimes(2)
+			},
+			f:         func(t testing.TB) {},
+			satisfied: true,
+		},
+		{
+			name: "MinTimes and MaxTimes",
+			expect: func(e *Expectations) {
+				e.Call("Helper").MinTimes(2).MaxTimes(3)
+			},
+			f: func(t testing.TB) { t.Helper() },
+			want: []string{
+				"mocktesting: missing call to Helper: expected between 2 " +
+					"and 3, got 1",
+			},
+		},
+		{
+			name: "MaxTimes(1) then MinTimes(1) with too many calls",
+			expect: func(e *Expectations) {
+				e.Call("Log").MaxTimes(1).MinTimes(1)
+			},
+			f: func(t testing.TB) {
+				t.Log("foo")
+				t.Log("bar")
+			},
+			want: []string{
+				`mocktesting: unexpected call to Log("bar") at ` +
+					"expectation_test.go:",
+			},
+		},
+		{
+			name: "MinTimes(1) then MaxTimes(1) with too many calls",
+			expect: func(e *Expectations) {
+				e.Call("Log").MinTimes(1).MaxTimes(1)
+			},
+			f: func(t testing.TB) {
+				t.Log("foo")
+				t.Log("bar")
+			},
+			want: []string{
+				`mocktesting: unexpected call to Log("bar") at ` +
+					"expectation_test.go:",
+			},
+		},
+		{
+			name: "MaxTimes(1) then MinTimes(1) without calls",
+			expect: func(e *Expectations) {
+				e.Call("Log").MaxTimes(1).MinTimes(1)
+			},
+			f: func(t testing.TB) {},
+			want: []string{
+				"mocktesting: missing call to Log: expected exactly 1, got 0",
+			},
+		},
+		{
+			name: "MinTimes(1) then MaxTimes(1) without calls",
+			expect: func(e *Expectations) {
+				e.Call("Log").MinTimes(1).MaxTimes(1)
+			},
+			f: func(t testing.TB) {},
+			want: []string{
+				"mocktesting: missing call to Log: expected exactly 1, got 0",
+			},
+		},
+		{
+			name: "MinTimes(1) then MaxTimes(1) with one call",
+			expect: func(e *Expectations) {
+				e.Call("Log").MinTimes(1).MaxTimes(1)
+			},
+			f:         func(t testing.TB) { t.Log("foo") },
+			satisfied: true,
+		},
+		{
+			name: "AnyTimes",
+			expect: func(e *Expectations) {
+				e.Call("Helper").AnyTimes()
+			},
+			f: func(t testing.TB) {
+				for i := 0; i < 10; i++ {
+					t.Helper()
+				}
+			},
+			satisfied: true,
+		},
+		{
+			name: "WithArgs",
+			expect: func(e *Expectations) {
+				e.Call("Errorf").WithArgs("%s is %d", "foo", 42)
+			},
+			f: func(t testing.TB) {
+				t.Errorf("%s is %d", "foo", 42)
+			},
+			satisfied: true,
+		},
+		{
+			name: "WithArgs mismatch",
+			expect: func(e *Expectations) {
+				e.Call("Errorf").WithArgs("%s is %d", "foo", 42)
+			},
+			f: func(t testing.TB) {
+				t.Errorf("%s is %d", "foo", 43)
+			},
+			want: []string{
+				`mocktesting: unexpected call to Errorf("%s is %d", "foo", ` +
+					"43) at expectation_test.go:",
+				"mocktesting: missing call to Errorf(args matching " +
+					"[func(interface {}) bool, func(interface {}) bool, " +
+					"func(interface {}) bool]): expected exactly 1, got 0",
+			},
+		},
+		{
+			name: "WithArgsMatching",
+			expect: func(e *Expectations) {
+				e.Call("Error").WithArgsMatching(
+					"^foo",
+					regexp.MustCompile(`\d+`),
+					func(v interface{}) bool { return v == true },
+				)
+			},
+			f: func(t testing.TB) {
+				t.Error("foobar", 42, true)
+			},
+			satisfied: true,
+		},
+		{
+			name: "WithArgsMatching wrong number of args",
+			expect: func(e *Expectations) {
+				e.Call("Error").WithArgsMatching("^foo")
+			},
+			f: func(t testing.TB) {
+				t.Error("foobar", 42)
+			},
+			want: []string{
+				`mocktesting: unexpected call to Error("foobar", 42) at ` +
+					"expectation_test.go:",
+				`mocktesting: missing call to Error(args matching ["^foo"]): ` +
+					"expected exactly 1, got 0",
+			},
+		},
+		{
+			name: "WithMessageMatching formatted",
+			expect: func(e *Expectations) {
+				e.Call("Errorf").WithMessageMatching(`^foo is 42$`)
+			},
+			f: func(t testing.TB) {
+				t.Errorf("%s is %d", "foo", 42)
+			},
+			satisfied: true,
+		},
+		{
+			name: "WithMessageMatching unformatted",
+			expect: func(e *Expectations) {
+				e.Call("Log").WithMessageMatching(`^foo 42$`)
+			},
+			f: func(t testing.TB) {
+				t.Log("foo", 42)
+			},
+			satisfied: true,
+		},
+		{
+			name: "WithMessageMatching mismatch",
+			expect: func(e *Expectations) {
+				e.Call("Log").WithMessageMatching(`^foo 42$`)
+			},
+			f: func(t testing.TB) {
+				t.Log("foo", 43)
+			},
+			want: []string{
+				`mocktesting: unexpected call to Log("foo", 43) at ` +
+					"expectation_test.go:",
+				"mocktesting: missing call to Log with message matching " +
+					`"^foo 42$": expected exactly 1, got 0`,
+			},
+		},
+		{
+			name: "multiple expectations for one method",
+			expect: func(e *Expectations) {
+				e.Call("Log").WithMessageMatching("^foo")
+				e.Call("Log").WithMessageMatching("^bar").Times(2)
+			},
+			f: func(t testing.TB) {
+				t.Log("bar 1")
+				t.Log("foo")
+				t.Log("bar 2")
+			},
+			satisfied: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewT("reporter")
+			mt := NewT("TestFoo", WithTestingT(reporter))
+			tt.expect(mt.Expect())
+
+			Go(func() {
+				tt.f(mt)
+			})
+			mt.Finish()
+
+			assert.Equal(t, tt.satisfied, mt.Expect().Satisfied())
+			got := reporter.Output()
+			require.Len(t, got, len(tt.want), strings.Join(got, ""))
+			for i, want := range tt.want {
+				assert.Contains(t, got[i], want)
+			}
+		})
+	}
+}
+
+func TestT_Expect_sameInstance(t *testing.T) {
+	mt := NewT("TestFoo")
+
+	assert.Same(t, mt.Expect(), mt.Expect())
+}
+
+func TestT_Expect_reportedImmediately(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter))
+	mt.Expect().Call("FailNow").Never()
+
+	Go(func() {
+		mt.FailNow()
+	})
+
+	assert.True(t, reporter.Failed())
+	require.Len(t, mt.Expect().Unexpected(), 1)
+	assert.Equal(t, "FailNow", mt.Expect().Unexpected()[0].Method)
+}
+
+func TestT_Expect_impliedUnexpected(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter))
+	mt.Expect().Call("Fatal").Never()
+	mt.Expect().Call("FailNow").Never()
+
+	Go(func() {
+		mt.Fatal("foo")
+	})
+
+	assert.Len(t, reporter.Output(), 2)
+	require.Len(t, mt.Expect().Unexpected(), 1)
+	assert.Equal(t, "Fatal", mt.Expect().Unexpected()[0].Method)
+}
+
+func TestT_Expect_verifiedOnce(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter))
+	mt.Expect().Call("Helper")
+
+	mt.Finish()
+	mt.Finish()
+
+	assert.Len(t, reporter.Output(), 1)
+}
+
+func TestT_Expect_fatalOnlyTestingT(t *testing.T) {
+	reporter := &fatalOnlyT{}
+	mt := NewT("TestFoo", WithTestingT(reporter))
+	mt.Expect().Call("Helper").Never()
+
+	mt.Helper()
+
+	require.Len(t, reporter.fatals, 1)
+	assert.Contains(t,
+		reporter.fatals[0], "mocktesting: unexpected call to Helper() at",
+	)
+}
+
+func TestT_Expect_noTestingT(t *testing.T) {
+	mt := NewT("TestFoo")
+	mt.Expect().Call("Helper")
+
+	assert.PanicsWithError(t,
+		"mocktesting: missing call to Helper: expected exactly 1, got 0",
+		func() { mt.Finish() },
+	)
+}
+
+func TestExpectedCall_Count(t *testing.T) {
+	mt := NewT("TestFoo")
+	ec := mt.Expect().Call("Log").WithArgs("foo").AnyTimes()
+
+	mt.Log("foo")
+	mt.Log("foo")
+
+	assert.Equal(t, 2, ec.Count())
+}
+
+func TestExpectedCall_WithArgsMatching_invalid(t *testing.T) {
+	mt := NewT("TestFoo")
+
+	assert.PanicsWithValue(t,
+		"mocktesting: unsupported arg matcher type int",
+		func() { mt.Expect().Call("Log").WithArgsMatching(42) },
+	)
+	assert.Panics(t, func() {
+		mt.Expect().Call("Log").WithArgsMatching("(")
+	})
+}
+
+func Test_callMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Call
+		want string
+	}{
+		{
+			name: "no args",
+			c:    Call{Method: "Log"},
+			want: "",
+		},
+		{
+			name: "unformatted",
+			c:    Call{Method: "Error", Args: []interface{}{"foo", 42}},
+			want: "foo 42",
+		},
+		{
+			name: "formatted",
+			c: Call{
+				Method: "Skipf",
+				Args:   []interface{}{"%s: %v", "foo", errors.New("bar")},
+			},
+			want: "foo: bar",
+		},
+		{
+			name: "formatted without args",
+			c:    Call{Method: "Fatalf"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := callMessage(tt.c)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
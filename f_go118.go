@@ -0,0 +1,184 @@
+//go:build go1.18
+// +build go1.18
+
+package mocktesting
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// supportedFuzzTypes represents all of the types which *testing.F supports as
+// seed corpus values and fuzz target arguments.
+var supportedFuzzTypes = map[reflect.Type]bool{
+	reflect.TypeOf(([]byte)("")):  true,
+	reflect.TypeOf((string)("")):  true,
+	reflect.TypeOf((bool)(false)): true,
+	reflect.TypeOf((byte)(0)):     true,
+	reflect.TypeOf((rune)(0)):     true,
+	reflect.TypeOf((float32)(0)):  true,
+	reflect.TypeOf((float64)(0)):  true,
+	reflect.TypeOf((int)(0)):      true,
+	reflect.TypeOf((int8)(0)):     true,
+	reflect.TypeOf((int16)(0)):    true,
+	reflect.TypeOf((int32)(0)):    true,
+	reflect.TypeOf((int64)(0)):    true,
+	reflect.TypeOf((uint)(0)):     true,
+	reflect.TypeOf((uint8)(0)):    true,
+	reflect.TypeOf((uint16)(0)):   true,
+	reflect.TypeOf((uint32)(0)):   true,
+	reflect.TypeOf((uint64)(0)):   true,
+}
+
+// F is a fake/mock implementation of *testing.F. It embeds *T, so all methods
+// and inspection methods of *T are also available on *F.
+//
+// Fuzz() does not perform any actual fuzzing. It runs the fuzz target once for
+// each seed corpus entry given to Add(), just like "go test" does when not
+// fuzzing. Each input is run as a sub-test with its own *T instance, which can
+// be inspected with Subtests().
+type F struct {
+	// State - Fields which record how F has been modified via method calls.
+	seeds      [][]interface{}
+	fuzzCalled bool
+
+	*T
+}
+
+// NewF returns a new *F instance with the given name, configured with the
+// given options.
+func NewF(name string, options ...Option) *F {
+	return &F{T: NewT(name, options...)}
+}
+
+// Add records the given args as a seed corpus entry, which can be inspected
+// with Seeds().
+//
+// Like *testing.F, it panics if any of the args are of a type which is not
+// supported for fuzzing.
+func (f *F) Add(args ...interface{}) {
+	f.record("Add", args...)
+
+	for _, arg := range args {
+		if t := reflect.TypeOf(arg); !supportedFuzzTypes[t] {
+			panic(fmt.Sprintf("testing: unsupported type to Add %v", t))
+		}
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	f.seeds = append(f.seeds, args)
+}
+
+// Fuzz runs the fuzz target ff once for each seed corpus entry given to Add().
+// The first argument of ff must be *mocktesting.T, or an interface which it
+// implements, like testing.TB. Remaining arguments must match the types of
+// values in each seed corpus entry.
+//
+// Like *testing.F, it panics if ff is not a valid fuzz target, or if called
+// more than once. If any seed corpus entry does not match the arguments of ff,
+// the *F instance is failed with Fatal(), aborting the current goroutine.
+//
+// Each seed corpus entry is run as a sub-test named "seed#N", where N is the
+// index of the entry, in the same way Run() runs sub-tests.
+func (f *F) Fuzz(ff interface{}) {
+	f.record("Fuzz", ff)
+
+	if f.fuzzCalled {
+		panic("testing: F.Fuzz called more than once")
+	}
+	f.fuzzCalled = true
+	if f.isFailed() {
+		return
+	}
+
+	fn := reflect.ValueOf(ff)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func {
+		panic("testing: F.Fuzz must receive a function")
+	}
+	tType := reflect.TypeOf(f.T)
+	if fnType.NumIn() < 2 ||
+		(fnType.In(0) != tType && (fnType.In(0).Kind() != reflect.Interface ||
+			!tType.Implements(fnType.In(0)))) {
+		panic("testing: fuzz target must receive at least two arguments, " +
+			"where the first argument is a *T")
+	}
+	if fnType.NumOut() != 0 {
+		panic("testing: fuzz target must not return a value")
+	}
+
+	var types []reflect.Type
+	for i := 1; i < fnType.NumIn(); i++ {
+		t := fnType.In(i)
+		if !supportedFuzzTypes[t] {
+			panic(fmt.Sprintf("testing: unsupported type for fuzzing %v", t))
+		}
+		types = append(types, t)
+	}
+
+	seeds := f.Seeds()
+	for _, values := range seeds {
+		if err := checkCorpus(values, types); err != nil {
+			f.fatal(lnEntry(EntryFatal, []interface{}{err}))
+
+			return
+		}
+	}
+
+	for i, values := range seeds {
+		subtest := f.newSubtest(fmt.Sprintf("seed#%d", i))
+		args := make([]reflect.Value, 0, len(values)+1)
+		args = append(args, reflect.ValueOf(subtest))
+		for _, v := range values {
+			args = append(args, reflect.ValueOf(v))
+		}
+
+		f.runSubtest(subtest, func() {
+			fn.Call(args)
+		})
+	}
+}
+
+// checkCorpus verifies that the given corpus entry values match the given fuzz
+// target argument types.
+func checkCorpus(values []interface{}, types []reflect.Type) error {
+	if len(values) != len(types) {
+		return fmt.Errorf(
+			"wrong number of values in corpus entry: %d, want %d",
+			len(values), len(types),
+		)
+	}
+
+	valuesT := make([]reflect.Type, len(values))
+	for i, v := range values {
+		valuesT[i] = reflect.TypeOf(v)
+	}
+	for i := range types {
+		if valuesT[i] != types[i] {
+			return fmt.Errorf(
+				"mismatched types in corpus entry: %v, want %v",
+				valuesT, types,
+			)
+		}
+	}
+
+	return nil
+}
+
+//
+// Inspection Methods which are not part of the *testing.F type.
+//
+
+// Seeds returns a slice of seed corpus entries given to Add().
+func (f *F) Seeds() [][]interface{} {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+
+	return f.seeds
+}
+
+// Ensure F struct implements testing.TB interface.
+var _ testing.TB = (*F)(nil)
@@ -0,0 +1,44 @@
+//go:build go1.18
+// +build go1.18
+
+package mocktesting_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+)
+
+func ExampleF() {
+	fuzzUpper := func(f *mocktesting.F, seeds ...string) {
+		for _, s := range seeds {
+			f.Add(s)
+		}
+		f.Fuzz(func(t testing.TB, s string) {
+			if strings.ToUpper(s) != s {
+				t.Errorf("%q is not upper case", s)
+			}
+		})
+	}
+
+	mf := mocktesting.NewF("FuzzUpper")
+	fuzzUpper(mf, "FOO", "bar")
+
+	fmt.Printf("Name: %s\n", mf.Name())
+	fmt.Printf("Failed: %+v\n", mf.Failed())
+	fmt.Printf("Seeds: %+v\n", mf.Seeds())
+	for _, st := range mf.Subtests() {
+		fmt.Printf("- %s: failed=%+v output=%q\n",
+			st.Name(), st.Failed(), st.Output(),
+		)
+	}
+
+	// Output:
+	// Name: FuzzUpper
+	// Failed: true
+	// Seeds: [[FOO] [bar]]
+	// - FuzzUpper/seed#0: failed=false output=[]
+	// - FuzzUpper/seed#1: failed=true output=["\"bar\" is not upper case\n"]
+}
@@ -0,0 +1,264 @@
+//go:build go1.18
+// +build go1.18
+
+package mocktesting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewF(t *testing.T) {
+	f := NewF("FuzzFoo bar", WithNoAbort())
+
+	assert.Equal(t, "FuzzFoo_bar", f.Name())
+	assert.False(t, f.abort)
+	assert.Nil(t, f.Seeds())
+}
+
+func TestF_Add(t *testing.T) {
+	tests := []struct {
+		name      string
+		seeds     [][]interface{}
+		wantPanic interface{}
+	}{
+		{
+			name:  "single value",
+			seeds: [][]interface{}{{"foo"}},
+		},
+		{
+			name: "multiple values",
+			seeds: [][]interface{}{
+				{"foo", 1, []byte("bar")},
+				{"baz", 2, []byte(nil)},
+			},
+		},
+		{
+			name: "all supported types",
+			seeds: [][]interface{}{{
+				[]byte("a"), "b", true, byte(1), rune(2), float32(3),
+				float64(4), int(5), int8(6), int16(7), int32(8), int64(9),
+				uint(10), uint8(11), uint16(12), uint32(13), uint64(14),
+			}},
+		},
+		{
+			name:      "unsupported type",
+			seeds:     [][]interface{}{{[]string{"foo"}}},
+			wantPanic: "testing: unsupported type to Add []string",
+		},
+		{
+			name:      "nil value",
+			seeds:     [][]interface{}{{nil}},
+			wantPanic: "testing: unsupported type to Add <nil>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewF("FuzzAdd")
+
+			add := func() {
+				for _, seed := range tt.seeds {
+					f.Add(seed...)
+				}
+			}
+
+			if tt.wantPanic != nil {
+				assert.PanicsWithValue(t, tt.wantPanic, add)
+				assert.Nil(t, f.Seeds())
+			} else {
+				add()
+				assert.Equal(t, tt.seeds, f.Seeds())
+			}
+			assert.Len(t, f.CallsTo("Add"), len(tt.seeds))
+		})
+	}
+}
+
+func TestF_Fuzz(t *testing.T) {
+	type result struct {
+		name   string
+		failed bool
+		output []string
+	}
+	tests := []struct {
+		name        string
+		seeds       [][]interface{}
+		ff          interface{}
+		wantFailed  bool
+		wantAborted bool
+		wantOutput  []string
+		wantResults []result
+	}{
+		{
+			name: "no seeds",
+			ff:   func(t *T, s string) { t.Log(s) },
+		},
+		{
+			name:  "*T argument",
+			seeds: [][]interface{}{{"foo"}, {"bar"}},
+			ff:    func(t *T, s string) { t.Log(s) },
+			wantResults: []result{
+				{name: "FuzzFoo/seed#0", output: []string{"foo\n"}},
+				{name: "FuzzFoo/seed#1", output: []string{"bar\n"}},
+			},
+		},
+		{
+			name:  "testing.TB argument",
+			seeds: [][]interface{}{{"foo", 1}, {"bar", 2}},
+			ff: func(t testing.TB, s string, n int) {
+				if n > 1 {
+					t.Errorf("%s is too big: %d", s, n)
+				}
+			},
+			wantFailed: true,
+			wantResults: []result{
+				{name: "FuzzFoo/seed#0"},
+				{
+					name:   "FuzzFoo/seed#1",
+					failed: true,
+					output: []string{"bar is too big: 2\n"},
+				},
+			},
+		},
+		{
+			name:  "fatal in target",
+			seeds: [][]interface{}{{[]byte("a")}, {[]byte("b")}},
+			ff: func(t *T, b []byte) {
+				t.Fatal(string(b))
+				t.Log("unreachable")
+			},
+			wantFailed: true,
+			wantResults: []result{
+				{name: "FuzzFoo/seed#0", failed: true, output: []string{"a\n"}},
+				{name: "FuzzFoo/seed#1", failed: true, output: []string{"b\n"}},
+			},
+		},
+		{
+			name:        "wrong number of values",
+			seeds:       [][]interface{}{{"foo", 1}},
+			ff:          func(t *T, s string) {},
+			wantFailed:  true,
+			wantAborted: true,
+			wantOutput: []string{
+				"wrong number of values in corpus entry: 2, want 1\n",
+			},
+		},
+		{
+			name:        "mismatched types",
+			seeds:       [][]interface{}{{"foo"}, {1}},
+			ff:          func(t *T, s string) {},
+			wantFailed:  true,
+			wantAborted: true,
+			wantOutput: []string{
+				"mismatched types in corpus entry: [int], want [string]\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewF("FuzzFoo")
+			for _, seed := range tt.seeds {
+				f.Add(seed...)
+			}
+
+			Go(func() {
+				f.Fuzz(tt.ff)
+			})
+
+			assert.Equal(t, tt.wantFailed, f.Failed())
+			assert.Equal(t, tt.wantAborted, f.Aborted())
+			assert.Equal(t, tt.wantOutput, f.Output())
+			require.Len(t, f.Subtests(), len(tt.wantResults))
+			for i, want := range tt.wantResults {
+				sub := f.Subtests()[i]
+				assert.Equal(t, want.name, sub.Name())
+				assert.Equal(t, want.failed, sub.Failed())
+				assert.Equal(t, want.output, sub.Output())
+			}
+		})
+	}
+}
+
+func TestF_Fuzz_panics(t *testing.T) {
+	tests := []struct {
+		name      string
+		ff        interface{}
+		wantPanic string
+	}{
+		{
+			name:      "not a function",
+			ff:        "foo",
+			wantPanic: "testing: F.Fuzz must receive a function",
+		},
+		{
+			name: "no arguments",
+			ff:   func() {},
+			wantPanic: "testing: fuzz target must receive at least two " +
+				"arguments, where the first argument is a *T",
+		},
+		{
+			name: "only *T argument",
+			ff:   func(t *T) {},
+			wantPanic: "testing: fuzz target must receive at least two " +
+				"arguments, where the first argument is a *T",
+		},
+		{
+			name: "first argument not *T",
+			ff:   func(s string, t *T) {},
+			wantPanic: "testing: fuzz target must receive at least two " +
+				"arguments, where the first argument is a *T",
+		},
+		{
+			name: "first argument *testing.T",
+			ff:   func(t *testing.T, s string) {},
+			wantPanic: "testing: fuzz target must receive at least two " +
+				"arguments, where the first argument is a *T",
+		},
+		{
+			name:      "returns value",
+			ff:        func(t *T, s string) error { return nil },
+			wantPanic: "testing: fuzz target must not return a value",
+		},
+		{
+			name:      "unsupported argument type",
+			ff:        func(t *T, s []string) {},
+			wantPanic: "testing: unsupported type for fuzzing []string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewF("FuzzPanics")
+
+			assert.PanicsWithValue(t, tt.wantPanic, func() {
+				f.Fuzz(tt.ff)
+			})
+		})
+	}
+}
+
+func TestF_Fuzz_calledTwice(t *testing.T) {
+	f := NewF("FuzzTwice")
+	f.Add("foo")
+	ff := func(t *T, s string) {}
+
+	f.Fuzz(ff)
+
+	assert.PanicsWithValue(t, "testing: F.Fuzz called more than once",
+		func() { f.Fuzz(ff) },
+	)
+	assert.Len(t, f.Subtests(), 1)
+	assert.Len(t, f.CallsTo("Fuzz"), 2)
+}
+
+func TestF_Fuzz_failedBefore(t *testing.T) {
+	f := NewF("FuzzFailed")
+	f.Add("foo")
+	f.Error("setup failed")
+
+	f.Fuzz(func(t *T, s string) {})
+
+	assert.True(t, f.Failed())
+	assert.Empty(t, f.Subtests())
+}
@@ -0,0 +1,164 @@
+package mocktesting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a testsuite element of a JUnit XML report.
+type JUnitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a testcase element of a JUnit XML report.
+type JUnitTestCase struct {
+	XMLName   xml.Name      `xml:"testcase"`
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure is a failure element of a JUnit XML report.
+type JUnitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// JUnitSkipped is a skipped element of a JUnit XML report.
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnit returns a JUnit XML report for the given *T instances and their
+// sub-tests. Each *T instance becomes a testsuite, containing a testcase for
+// itself and for each of its sub-tests, recursively.
+//
+// The message of a failure is the text of the first entry produced by Error(),
+// Fatal() or their formatted variants, or "Failed" if there is none, like when
+// only a sub-test failed. The contents of a failure is all output of the
+// test, formatted like Transcript() does. The message of a skipped element is
+// the text of the entry produced by Skip() or Skipf(). Times are taken from
+// Duration(), and the timestamp of each testsuite from Started().
+func JUnit(tests ...*T) *JUnitTestSuites {
+	r := &JUnitTestSuites{}
+
+	var total time.Duration
+	for _, t := range tests {
+		suite := t.junitSuite()
+		r.Suites = append(r.Suites, suite)
+		r.Tests += suite.Tests
+		r.Failures += suite.Failures
+		r.Skipped += suite.Skipped
+		total += t.Duration()
+	}
+	r.Time = junitTime(total)
+
+	return r
+}
+
+// WriteJUnit writes a JUnit XML report for the given *T instances and their
+// sub-tests to w, as returned by JUnit().
+func WriteJUnit(w io.Writer, tests ...*T) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(JUnit(tests...)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func (t *T) junitSuite() JUnitTestSuite {
+	suite := JUnitTestSuite{
+		Name: t.name,
+		Time: junitTime(t.Duration()),
+	}
+	if started := t.Started(); !started.IsZero() {
+		suite.Timestamp = started.Format(time.RFC3339)
+	}
+
+	var walk func(*T)
+	walk = func(t *T) {
+		tc := t.junitTestCase(suite.Name)
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		} else if tc.Skipped != nil {
+			suite.Skipped++
+		}
+
+		for _, subtest := range t.Subtests() {
+			walk(subtest)
+		}
+	}
+	walk(t)
+
+	return suite
+}
+
+func (t *T) junitTestCase(classname string) JUnitTestCase {
+	tc := JUnitTestCase{
+		Name:      t.name,
+		Classname: classname,
+		Time:      junitTime(t.Duration()),
+	}
+
+	switch t.Result() {
+	case "FAIL":
+		f := &JUnitFailure{Message: "Failed"}
+		if e := t.EntriesOf(EntryError, EntryFatal); len(e) > 0 {
+			f.Message = strings.TrimSuffix(e[0].Text, "\n")
+		}
+
+		var b strings.Builder
+		for _, e := range t.Entries() {
+			b.WriteString(decorate(e))
+		}
+		f.Contents = b.String()
+		tc.Failure = f
+	case "SKIP":
+		s := &JUnitSkipped{}
+		if e := t.EntriesOf(EntrySkip); len(e) > 0 {
+			s.Message = strings.TrimSuffix(e[0].Text, "\n")
+		}
+		tc.Skipped = s
+	}
+
+	return tc
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
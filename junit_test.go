@@ -0,0 +1,166 @@
+package mocktesting
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnit(t *testing.T) {
+	var lines [4]int
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestFoo", WithClock(clock))
+
+	lines[3] = callerLine()
+	mt.Log("starting")
+	mt.Run("pass", func(t testing.TB) {
+		clock.Advance(1234 * time.Millisecond)
+	})
+	mt.Run("fail", func(t testing.TB) {
+		lines[0] = callerLine()
+		t.Error("first error")
+		lines[1] = callerLine()
+		t.Errorf("second %s", "error")
+	})
+	mt.Run("skip", func(t testing.TB) {
+		lines[2] = callerLine()
+		t.Skip("not today")
+	})
+	clock.Advance(766 * time.Millisecond)
+	mt.Finish()
+
+	other := NewT("TestBar", WithClock(clock))
+	other.Fail()
+	other.Finish()
+
+	got := JUnit(mt, other)
+
+	want := &JUnitTestSuites{
+		Tests:    5,
+		Failures: 3,
+		Skipped:  1,
+		Time:     "2.000",
+		Suites: []JUnitTestSuite{
+			{
+				Name:      "TestFoo",
+				Tests:     4,
+				Failures:  2,
+				Skipped:   1,
+				Time:      "2.000",
+				Timestamp: fakeEpoch.Format(time.RFC3339),
+				TestCases: []JUnitTestCase{
+					{
+						Name:      "TestFoo",
+						Classname: "TestFoo",
+						Time:      "2.000",
+						Failure: &JUnitFailure{
+							Message: "Failed",
+							Contents: fmt.Sprintf(
+								"    junit_test.go:%d: starting\n", lines[3],
+							),
+						},
+					},
+					{
+						Name:      "TestFoo/pass",
+						Classname: "TestFoo",
+						Time:      "1.234",
+					},
+					{
+						Name:      "TestFoo/fail",
+						Classname: "TestFoo",
+						Time:      "0.000",
+						Failure: &JUnitFailure{
+							Message: "first error",
+							Contents: fmt.Sprintf(
+								"    junit_test.go:%d: first error\n"+
+									"    junit_test.go:%d: second error\n",
+								lines[0], lines[1],
+							),
+						},
+					},
+					{
+						Name:      "TestFoo/skip",
+						Classname: "TestFoo",
+						Time:      "0.000",
+						Skipped:   &JUnitSkipped{Message: "not today"},
+					},
+				},
+			},
+			{
+				Name:      "TestBar",
+				Tests:     1,
+				Failures:  1,
+				Time:      "0.000",
+				Timestamp: fakeEpoch.Add(2 * time.Second).Format(time.RFC3339),
+				TestCases: []JUnitTestCase{
+					{
+						Name:      "TestBar",
+						Classname: "TestBar",
+						Time:      "0.000",
+						Failure:   &JUnitFailure{Message: "Failed"},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func TestJUnit_unfinished(t *testing.T) {
+	mt := NewT("TestFoo", WithClock(NewFakeClock(fakeEpoch)))
+
+	got := JUnit(mt)
+
+	require.Len(t, got.Suites, 1)
+	assert.Equal(t, 1, got.Tests)
+	assert.Equal(t, "0.000", got.Time)
+	assert.Equal(t, []JUnitTestCase{
+		{Name: "TestFoo", Classname: "TestFoo", Time: "0.000"},
+	}, got.Suites[0].TestCases)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestFoo", WithClock(clock))
+
+	mt.Run("pass", func(t testing.TB) {
+		clock.Advance(1500 * time.Millisecond)
+	})
+	mt.Run("skip", func(t testing.TB) {
+		t.Skip("a & b")
+	})
+	mt.Finish()
+
+	var buf bytes.Buffer
+	err := WriteJUnit(&buf, mt)
+	require.NoError(t, err)
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="0" errors="0" skipped="1" time="1.500">
+	<testsuite name="TestFoo" tests="3" failures="0" errors="0" skipped="1" ` +
+		`time="1.500" timestamp="` + fakeEpoch.Format(time.RFC3339) + `">
+		<testcase name="TestFoo" classname="TestFoo" time="1.500"></testcase>
+		<testcase name="TestFoo/pass" classname="TestFoo" ` +
+		`time="1.500"></testcase>
+		<testcase name="TestFoo/skip" classname="TestFoo" time="0.000">
+			<skipped message="a &amp; b"></skipped>
+		</testcase>
+	</testsuite>
+</testsuites>
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteJUnit_error(t *testing.T) {
+	mt := NewT("TestFoo")
+	mt.Finish()
+
+	err := WriteJUnit(errWriter{}, mt)
+
+	assert.EqualError(t, err, "write failed")
+}
@@ -0,0 +1,144 @@
+package mocktesting
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test is a named test function which is run by *M.
+type Test struct {
+	Name string
+	F    func(testing.TB)
+}
+
+// M is a fake/mock implementation of *testing.M, for the purpose of testing
+// TestMain helpers. As such helpers would need to accept an interface instead
+// of *testing.M, a simple interface like the following should suffice:
+//
+//	type runner interface {
+//		Run() int
+//	}
+//
+// Instead of running the tests of the current package, Run() runs the
+// configured list of tests, each with its own *T instance.
+type M struct {
+	// Settings - These fields control the behavior of M.
+	tests   []Test
+	options []Option
+	clock   Clock
+
+	// State - Fields which record how M has been modified via method calls.
+	mux      sync.RWMutex
+	runAt    []time.Time
+	results  []*T
+	exitCode int
+}
+
+// NewM returns a new *M instance which runs the given tests. Each test is run
+// with a new *T instance, created with the given options.
+func NewM(tests []Test, options ...Option) *M {
+	// Apply options to a throwaway *T to pick up any Clock given with
+	// WithClock(), so Run() timestamps agree with those of the tests.
+	probe := &T{}
+	for _, opt := range options {
+		opt.apply(probe)
+	}
+
+	return &M{
+		tests:   tests,
+		options: options,
+		clock:   probe.clock,
+	}
+}
+
+// Run runs all configured tests in order, and returns an exit code of 1 if any
+// of them failed, otherwise 0.
+//
+// Each test function is run in its own goroutine via the Go() method of its *T
+// instance, and Finish() is called on the *T instance once it has returned. A
+// test function which panics marks its *T instance as failed, with the panic
+// value available via PanicValue(), and the remaining tests are still run.
+func (m *M) Run() int {
+	m.mux.Lock()
+	m.runAt = append(m.runAt, m.now())
+	m.mux.Unlock()
+
+	code := 0
+	for _, test := range m.tests {
+		t := NewT(test.Name, m.options...)
+		t.Go(func() {
+			defer t.recoverPanic()
+			test.F(t)
+		})
+		t.Finish()
+
+		if t.isFailed() {
+			code = 1
+		}
+
+		m.mux.Lock()
+		m.results = append(m.results, t)
+		m.mux.Unlock()
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.exitCode = code
+
+	return code
+}
+
+//
+// Inspection Methods which are not part of the *testing.M type.
+//
+
+// now returns the current time according to the Clock given with the
+// WithClock() option, or the system clock if it was not used.
+func (m *M) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+
+	return m.clock.Now()
+}
+
+// Ran returns true if Run() has been called.
+func (m *M) Ran() bool {
+	return m.RunCount() > 0
+}
+
+// RunCount returns the number of times Run() has been called.
+func (m *M) RunCount() int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	return len(m.runAt)
+}
+
+// RunAt returns the time of each call to Run().
+func (m *M) RunAt() []time.Time {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	return m.runAt
+}
+
+// Tests returns the *T instances of all tests run by Run(), in the order they
+// were run. If Run() has been called multiple times, tests from all runs are
+// returned.
+func (m *M) Tests() []*T {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	return m.results
+}
+
+// ExitCode returns the exit code returned by the most recent call to Run().
+func (m *M) ExitCode() int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	return m.exitCode
+}
@@ -0,0 +1,41 @@
+package mocktesting_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jimeh/go-mocktesting"
+)
+
+func ExampleM() {
+	type runner interface {
+		Run() int
+	}
+	var teardown bool
+	testMain := func(m runner) int {
+		code := m.Run()
+		teardown = true
+
+		return code
+	}
+
+	mm := mocktesting.NewM([]mocktesting.Test{
+		{Name: "TestFoo", F: func(t testing.TB) {}},
+		{Name: "TestBar", F: func(t testing.TB) { t.Error("oops") }},
+	})
+	code := testMain(mm)
+
+	fmt.Printf("Code: %d\n", code)
+	fmt.Printf("Ran: %+v\n", mm.Ran())
+	fmt.Printf("Teardown: %+v\n", teardown)
+	for _, mt := range mm.Tests() {
+		fmt.Printf("- %s: failed=%+v\n", mt.Name(), mt.Failed())
+	}
+
+	// Output:
+	// Code: 1
+	// Ran: true
+	// Teardown: true
+	// - TestFoo: failed=false
+	// - TestBar: failed=true
+}
@@ -0,0 +1,219 @@
+package mocktesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewM(t *testing.T) {
+	tests := []Test{{Name: "TestFoo", F: func(testing.TB) {}}}
+
+	m := NewM(tests, WithNoAbort())
+
+	assert.Len(t, m.tests, 1)
+	assert.Len(t, m.options, 1)
+	assert.False(t, m.Ran())
+	assert.Equal(t, 0, m.RunCount())
+	assert.Nil(t, m.RunAt())
+	assert.Nil(t, m.Tests())
+	assert.Equal(t, 0, m.ExitCode())
+}
+
+func TestM_Run(t *testing.T) {
+	type result struct {
+		name    string
+		failed  bool
+		skipped bool
+		aborted bool
+		output  []string
+	}
+	tests := []struct {
+		name        string
+		tests       []Test
+		options     []Option
+		want        int
+		wantResults []result
+	}{
+		{
+			name: "no tests",
+			want: 0,
+		},
+		{
+			name: "passing tests",
+			tests: []Test{
+				{Name: "TestFoo", F: func(t testing.TB) { t.Log("foo") }},
+				{Name: "TestBar", F: func(t testing.TB) {}},
+			},
+			want: 0,
+			wantResults: []result{
+				{name: "TestFoo", output: []string{"foo\n"}},
+				{name: "TestBar"},
+			},
+		},
+		{
+			name: "skipped test",
+			tests: []Test{
+				{Name: "TestFoo", F: func(t testing.TB) { t.Skip("nope") }},
+			},
+			want: 0,
+			wantResults: []result{
+				{
+					name:    "TestFoo",
+					skipped: true,
+					aborted: true,
+					output:  []string{"nope\n"},
+				},
+			},
+		},
+		{
+			name: "failing test",
+			tests: []Test{
+				{Name: "TestFoo", F: func(t testing.TB) { t.Fatal("oops") }},
+				{Name: "TestBar", F: func(t testing.TB) {}},
+			},
+			want: 1,
+			wantResults: []result{
+				{
+					name:    "TestFoo",
+					failed:  true,
+					aborted: true,
+					output:  []string{"oops\n"},
+				},
+				{name: "TestBar"},
+			},
+		},
+		{
+			name: "failing test with options",
+			tests: []Test{
+				{
+					Name: "TestFoo",
+					F: func(t testing.TB) {
+						t.Fatal("oops")
+						t.Log("continued")
+					},
+				},
+			},
+			options: []Option{WithNoAbort()},
+			want:    1,
+			wantResults: []result{
+				{
+					name:    "TestFoo",
+					failed:  true,
+					aborted: true,
+					output:  []string{"oops\n", "continued\n"},
+				},
+			},
+		},
+		{
+			name: "failing cleanup",
+			tests: []Test{
+				{
+					Name: "TestFoo",
+					F: func(t testing.TB) {
+						t.Cleanup(func() { t.Error("cleanup") })
+					},
+				},
+			},
+			want: 1,
+			wantResults: []result{
+				{name: "TestFoo", failed: true, output: []string{"cleanup\n"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewM(tt.tests, tt.options...)
+			before := time.Now()
+
+			got := m.Run()
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, m.ExitCode())
+			assert.True(t, m.Ran())
+			assert.Equal(t, 1, m.RunCount())
+			require.Len(t, m.RunAt(), 1)
+			assert.False(t, m.RunAt()[0].Before(before))
+			require.Len(t, m.Tests(), len(tt.wantResults))
+			for i, want := range tt.wantResults {
+				mt := m.Tests()[i]
+				assert.Empty(t, mt.CallsTo("Failed"))
+				assert.Equal(t, want.name, mt.Name())
+				assert.Equal(t, want.failed, mt.Failed())
+				assert.Equal(t, want.skipped, mt.Skipped())
+				assert.Equal(t, want.aborted, mt.Aborted())
+				assert.Equal(t, want.output, mt.Output())
+				assert.True(t, mt.Finished())
+			}
+		})
+	}
+}
+
+func TestM_Run_panic(t *testing.T) {
+	m := NewM([]Test{
+		{Name: "TestPanic", F: func(testing.TB) { panic("boom") }},
+		{Name: "TestBar", F: func(t testing.TB) { t.Log("bar") }},
+	})
+
+	code := m.Run()
+
+	assert.Equal(t, 1, code)
+	require.Len(t, m.Tests(), 2)
+	assert.True(t, m.Tests()[0].Failed())
+	assert.True(t, m.Tests()[0].Panicked())
+	assert.Equal(t, "boom", m.Tests()[0].PanicValue())
+	assert.True(t, m.Tests()[0].Finished())
+	assert.False(t, m.Tests()[1].Failed())
+	assert.Equal(t, []string{"bar\n"}, m.Tests()[1].Output())
+}
+
+func TestM_Run_clock(t *testing.T) {
+	clock := NewFakeClock(fakeEpoch)
+	m := NewM([]Test{
+		{
+			Name: "TestFoo",
+			F: func(t testing.TB) {
+				clock.Advance(time.Second)
+			},
+		},
+	}, WithClock(clock))
+
+	m.Run()
+	m.Run()
+
+	assert.Equal(t, []time.Time{
+		fakeEpoch,
+		fakeEpoch.Add(time.Second),
+	}, m.RunAt())
+	require.Len(t, m.Tests(), 2)
+	assert.Equal(t, fakeEpoch, m.Tests()[0].Started())
+	assert.Equal(t, fakeEpoch.Add(time.Second), m.Tests()[1].Started())
+}
+
+func TestM_Run_multiple(t *testing.T) {
+	fail := true
+	m := NewM([]Test{
+		{
+			Name: "TestFoo",
+			F: func(t testing.TB) {
+				if fail {
+					t.Error("failed")
+				}
+			},
+		},
+	})
+
+	assert.Equal(t, 1, m.Run())
+	fail = false
+	assert.Equal(t, 0, m.Run())
+
+	assert.Equal(t, 0, m.ExitCode())
+	assert.Equal(t, 2, m.RunCount())
+	require.Len(t, m.RunAt(), 2)
+	assert.False(t, m.RunAt()[1].Before(m.RunAt()[0]))
+	require.Len(t, m.Tests(), 2)
+	assert.True(t, m.Tests()[0].Failed())
+	assert.False(t, m.Tests()[1].Failed())
+}
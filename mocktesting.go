@@ -12,12 +12,80 @@ import (
 // This is essentially a helper function to avoid aborting the current goroutine
 // when a *T instance aborts the goroutine that any of FailNow(), Fatal(),
 // Fatalf(), SkipNow(), Skip(), or Skipf() are called from.
+//
+// The goroutine is treated as the test function goroutine of any *T instance
+// which is first used from within it, and which does not already have one set
+// by Run(), the Go() method of *T, or Wrap(). See WithGoroutineCheck() for
+// details.
 func Go(f func()) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+
+		id := goroutineID()
+		goroutines.start(id)
+		defer goroutines.end(id)
+
 		f()
 	}()
 	wg.Wait()
 }
+
+// goroutines tracks goroutines started by the package-level Go() function
+// which are still running, along with the *T instances bound to each of them.
+var goroutines = &goroutineRegistry{}
+
+type goroutineRegistry struct {
+	mux   sync.Mutex
+	bound map[uint64][]*T
+}
+
+func (r *goroutineRegistry) start(id uint64) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.bound == nil {
+		r.bound = map[uint64][]*T{}
+	}
+	r.bound[id] = nil
+}
+
+// end removes the goroutine from the registry, and unsets it as the test
+// function goroutine of all *T instances bound to it.
+func (r *goroutineRegistry) end(id uint64) {
+	r.mux.Lock()
+	ts := r.bound[id]
+	delete(r.bound, id)
+	r.mux.Unlock()
+
+	for _, t := range ts {
+		t.mux.Lock()
+		if t.owner == id {
+			t.owner = 0
+		}
+		t.mux.Unlock()
+	}
+}
+
+// running returns true if the goroutine was started by Go() and is still
+// running.
+func (r *goroutineRegistry) running(id uint64) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	_, ok := r.bound[id]
+
+	return ok
+}
+
+// bind records that t has the goroutine as its test function goroutine, so it
+// is unset once the goroutine ends.
+func (r *goroutineRegistry) bind(id uint64, t *T) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.bound[id]; ok {
+		r.bound[id] = append(r.bound[id], t)
+	}
+}
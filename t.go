@@ -1,11 +1,14 @@
 package mocktesting
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"testing"
@@ -38,6 +41,18 @@ type T struct {
 	testingT    TestingT
 	deadline    time.Time
 	timeout     bool
+	timeoutDur  time.Duration
+	clock       Clock
+	pause       bool
+	helperCheck bool
+	latePanic   bool
+	ownerCheck  bool
+	realSetenv  bool
+	realChdir   bool
+	benchN      int
+	watchdog    bool
+	tee         *outputTee
+	wrapped     testing.TB
 
 	// State - Fields which record how T has been modified via method calls.
 	mux      sync.RWMutex
@@ -45,16 +60,96 @@ type T struct {
 	failed   int
 	parallel bool
 	output   []string
+	entries  []Entry
 	helpers  []string
+	missing  []string
 	aborted  bool
 	cleanups []func()
+	finished bool
 	env      map[string]string
+	chdirs   []string
 	subtests []*T
 	tempdirs []string
+	calls    []Call
+
+	// completed is set once Finish() has returned, after which calls to
+	// methods like Log() and Fail() are recorded in lateCalls.
+	completed bool
+	lateCalls []LateCall
+
+	// owner is the ID of the goroutine running the test function of the *T
+	// instance, as started by Run() or Go(). Calls to methods which abort the
+	// goroutine from any other goroutine are recorded in nonTestCalls.
+	owner        uint64
+	nonTestCalls []Call
+
+	// expectations holds calls the *T instance is expected to receive, as
+	// declared via Expect().
+	expectations *Expectations
+
+	// ctx is the context returned by Context(), which is created on first use
+	// and canceled by Finish() via cancel.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// denyParallel is set when the *T instance has modified process-wide
+	// state which would conflict with Parallel().
+	denyParallel bool
 
 	// subtestNames is used to ensure subtests do not have conflicting names.
 	subtestNames map[string]bool
 
+	// pendingCleanups holds the functions in cleanups which have not yet been
+	// executed by Finish(), which pops and runs them one at a time.
+	pendingCleanups []func()
+
+	// cleanupPanics holds values recovered from panicking cleanup functions.
+	cleanupPanics []interface{}
+
+	// panicked, panicValue and panicStack record a panic recovered from the
+	// test function of a sub-test started by Run().
+	panicked   bool
+	panicValue interface{}
+	panicStack []byte
+
+	// started and ended are the times at which the *T instance was created, and
+	// at which Finish() completed.
+	started time.Time
+	ended   time.Time
+
+	// runSeq, pauseSeq, contSeq and endSeq are numbers from the sequence used
+	// by Call.Seq, marking when the *T instance was created, paused and
+	// continued by Parallel(), and when Finish() completed. They are used to
+	// order events when rendering a transcript.
+	runSeq   uint64
+	pauseSeq uint64
+	contSeq  uint64
+	endSeq   uint64
+
+	// timedOut and timeoutDump record that the deadline passed while waiting
+	// on a test function when the WithTimeoutEnforcement() option is used.
+	timedOut    bool
+	timeoutDump []byte
+
+	// parent is the *T instance which created this *T instance via Run().
+	parent *T
+
+	// pauseC is closed by Parallel() to signal Run() that the sub-test has
+	// paused, and resumeC is closed by the parent once its test function has
+	// returned, allowing paused sub-tests to continue. Both are only set on
+	// sub-tests when the WithParallelSubtests() option is used.
+	pauseC  chan struct{}
+	resumeC chan struct{}
+
+	// done is closed once a sub-test function has returned and Finish() has
+	// been called on the sub-test.
+	done chan struct{}
+
+	// barrier is given to sub-tests as their resumeC, and paused holds all
+	// sub-tests waiting on it. Both are reset by Finish().
+	barrier chan struct{}
+	paused  []*T
+
 	// mkdirTempFunc is used by the TempDir function instead of ioutil.TempDir()
 	// if it is not nil. This is only used by tests for TempDir itself to ensure
 	// it behaves correctly if temp directory creation fails.
@@ -74,14 +169,22 @@ func NewT(name string, options ...Option) *T {
 		name:        strings.ReplaceAll(name, " ", "_"),
 		abort:       true,
 		baseTempdir: os.TempDir(),
-		deadline:    time.Now().Add(10 * time.Minute),
 		timeout:     true,
+		timeoutDur:  10 * time.Minute,
+		runSeq:      nextSeq(),
 	}
 
 	for _, opt := range options {
 		opt.apply(t)
 	}
 
+	// Timeouts are relative to the clock, which may have been set by an option
+	// given after the timeout.
+	t.started = t.now()
+	if t.timeoutDur > 0 {
+		t.deadline = t.started.Add(t.timeoutDur)
+	}
+
 	return t
 }
 
@@ -106,9 +209,11 @@ func WithTimeout(d time.Duration) Option {
 	return optionFunc(func(t *T) {
 		if d > 0 {
 			t.timeout = true
-			t.deadline = time.Now().Add(d)
+			t.timeoutDur = d
+			t.deadline = t.now().Add(d)
 		} else {
 			t.timeout = false
+			t.timeoutDur = 0
 			t.deadline = time.Time{}
 		}
 	})
@@ -125,6 +230,7 @@ func WithTimeout(d time.Duration) Option {
 // If this option is not used, the default timeout value is set to 10 minutes.
 func WithDeadline(d time.Time) Option {
 	return optionFunc(func(t *T) {
+		t.timeoutDur = 0
 		if d != (time.Time{}) {
 			t.timeout = true
 			t.deadline = d
@@ -135,6 +241,37 @@ func WithDeadline(d time.Time) Option {
 	})
 }
 
+// WithClock sets the Clock used by the *T instance as its source of time. It is
+// used to determine the deadline set by WithTimeout(), the time of recorded
+// calls, the start and end times of the *T instance and its sub-tests, and to
+// enforce timeouts when the WithTimeoutEnforcement() option is used.
+//
+// Combined with a *FakeClock, it allows testing helpers which depend on
+// Deadline() or measure elapsed time deterministically.
+//
+// If this option is not used, the real system clock is used.
+func WithClock(c Clock) Option {
+	return optionFunc(func(t *T) {
+		t.clock = c
+	})
+}
+
+// WithTimeoutEnforcement enables a watchdog which enforces the deadline set by
+// WithTimeout() or WithDeadline(). Without it, the deadline only determines the
+// return values of Deadline().
+//
+// When enabled, Run(), Go() and Finish() stop waiting on test, sub-test and
+// cleanup functions once the deadline has passed. The *T instance is then
+// marked as failed and timed out, and a dump of all goroutine stacks is
+// captured, which can be inspected with TimedOut() and TimeoutDump(). Control
+// returns to the caller, while the hung function is left running in its own
+// goroutine.
+func WithTimeoutEnforcement() Option {
+	return optionFunc(func(t *T) {
+		t.watchdog = true
+	})
+}
+
 // WithNoAbort disables aborting the current goroutine with runtime.Goexit()
 // when SkipNow or FailNow is called. This should be used with care, as it
 // causes behavior to diverge from normal *tesing.T, as code after calling
@@ -145,6 +282,78 @@ func WithNoAbort() Option {
 	})
 }
 
+// WithParallelSubtests enables *testing.T-like semantics for sub-tests which
+// call Parallel(). Instead of running to completion, a sub-test calling
+// Parallel() is paused, and Run() returns immediately. All paused sub-tests
+// resume together once the parent's test function has returned, and the parent
+// waits for them to complete before running its own cleanup functions.
+//
+// For a *T instance created with NewT(), there is no test function to return
+// from, so paused sub-tests resume when Finish() is called on it instead.
+//
+// If this option is not used, Parallel() only records that it has been called,
+// and Run() always runs the sub-test to completion before returning.
+func WithParallelSubtests() Option {
+	return optionFunc(func(t *T) {
+		t.pause = true
+	})
+}
+
+// WithHelperCheck enables detection of helper functions which do not call
+// Helper(). When output is produced by Log(), Error(), Fatal(), Skip() or their
+// formatted variants, every function on the stack between the test function and
+// the call to *T is expected to have called Helper().
+//
+// The test function is considered to be the outermost function on the stack of
+// the goroutine, before reaching the function which started the goroutine, like
+// Run() or Go(). Functions which have not called Helper() can be inspected with
+// MissingHelpers().
+func WithHelperCheck() Option {
+	return optionFunc(func(t *T) {
+		t.helperCheck = true
+	})
+}
+
+// WithLateCallPanic makes calls to Log(), Error(), Fatal(), Skip(), their
+// formatted variants, Fail() and FailNow() panic when made after the *T
+// instance has completed, just like *testing.T does. For example:
+//
+//	Log in goroutine after TestFoo has completed: hello
+//
+// A *T instance has completed once Finish() has returned, which for sub-tests
+// started by Run() happens right after the sub-test function has returned.
+//
+// If this option is not used, such calls are only recorded, and can be
+// inspected with LateCalls().
+func WithLateCallPanic() Option {
+	return optionFunc(func(t *T) {
+		t.latePanic = true
+	})
+}
+
+// WithGoroutineCheck reports calls to FailNow(), SkipNow(), Fatal(), Skip() or
+// their formatted variants made from a goroutine other than the one running
+// the test function, via the TestingT given with the WithTestingT() option.
+// Such calls are a bug, as they abort the wrong goroutine, and the test
+// function keeps running. The TestingT's Errorf() method is used if it has one,
+// and Fatal() otherwise. If WithTestingT() is not used, such calls panic.
+//
+// The test function is run on the goroutine started by Run() or the Go()
+// method, or on the goroutine which called Wrap(). A *T instance created with
+// NewT() has no test function goroutine until it is first used from within a
+// goroutine started by the package-level Go() function, which then becomes its
+// test function goroutine until it exits. When this option is used, calls made
+// while the *T instance has no test function goroutine are reported as
+// unchecked, rather than passing silently.
+//
+// Regardless of this option, such calls are recorded, and can be inspected
+// with NonTestGoroutineCalls().
+func WithGoroutineCheck() Option {
+	return optionFunc(func(t *T) {
+		t.ownerCheck = true
+	})
+}
+
 // WithBaseTempdir sets the base directory that TempDir() creates temporary
 // directories within.
 //
@@ -169,13 +378,77 @@ func WithTestingT(testingT TestingT) Option {
 	})
 }
 
+// WithOutputTo forwards all output produced by Log(), Error(), Fatal(), Skip()
+// and their formatted variants to the given testing.TB as it happens, in
+// addition to recording it. Each line is prefixed with the name of the *T
+// instance and the location the output is attributed to, so output from
+// sub-tests started by Run() can be told apart.
+//
+// Lines are logged with tb.Log(), which prefixes them with a location of its
+// own. As the functions within mocktesting which produce output are marked as
+// helpers on tb, that location is the call to the *T instance's method in the
+// test or test helper.
+//
+// This option can be used multiple times, and combined with
+// WithOutputWriter().
+func WithOutputTo(tb testing.TB) Option {
+	return optionFunc(func(t *T) {
+		if t.tee == nil {
+			t.tee = &outputTee{}
+		}
+		t.tee.tbs = append(t.tee.tbs, tb)
+	})
+}
+
+// WithOutputWriter forwards all output to the given io.Writer in the same way
+// as WithOutputTo() does.
+//
+// This option can be used multiple times, and combined with WithOutputTo().
+func WithOutputWriter(w io.Writer) Option {
+	return optionFunc(func(t *T) {
+		if t.tee == nil {
+			t.tee = &outputTee{}
+		}
+		t.tee.writers = append(t.tee.writers, w)
+	})
+}
+
 func (t *T) goexit() {
+	t.mux.Lock()
 	t.aborted = true
+	skipped := t.skipped
+	t.mux.Unlock()
+
 	if t.abort {
+		if t.wrapped != nil {
+			if skipped {
+				t.wrapped.SkipNow()
+			}
+			t.wrapped.FailNow()
+		}
 		runtime.Goexit()
 	}
 }
 
+// parallelConflict is the panic message used by *testing.T when Parallel() is
+// used together with methods that modify process-wide state.
+const parallelConflict = "testing: test using t.Setenv or t.Chdir can not " +
+	"use t.Parallel"
+
+// checkParallel panics if the *T instance or any of its parents have called
+// Parallel(), and otherwise prevents Parallel() from being called on the *T
+// instance, just like *testing.T does for methods which modify process-wide
+// state.
+func (t *T) checkParallel() {
+	for c := t; c != nil; c = c.parent {
+		if c.Paralleled() {
+			panic(parallelConflict)
+		}
+	}
+
+	t.denyParallel = true
+}
+
 func (t *T) internalError(err error) {
 	err = fmt.Errorf("mocktesting: %w", err)
 
@@ -186,48 +459,106 @@ func (t *T) internalError(err error) {
 	}
 }
 
+// errorfer is implemented by TestingT values which can report errors without
+// aborting, like *testing.T.
+type errorfer interface {
+	Errorf(format string, args ...interface{})
+}
+
+// reportf reports a misuse of the *T instance via its TestingT, using Errorf()
+// if available and Fatal() otherwise, or panics if it does not have one.
+func (t *T) reportf(format string, args ...interface{}) {
+	err := fmt.Errorf("mocktesting: "+format, args...)
+
+	switch tt := t.testingT.(type) {
+	case nil:
+		panic(err)
+	case errorfer:
+		tt.Errorf("%s", err)
+	default:
+		tt.Fatal(err)
+	}
+}
+
 // Name returns the name given to the *T instance.
 func (t *T) Name() string {
+	t.record("Name")
+
 	return t.name
 }
 
 // Name returns the time at which the *T instance is set to timeout. If no
 // timeout is set, the bool return value is false, otherwise it is true.
 func (t *T) Deadline() (time.Time, bool) {
+	t.record("Deadline")
+
 	return t.deadline, t.timeout
 }
 
 // Error logs the given args with Log(), and then calls Fail() to mark the *T
 // instance as failed.
 func (t *T) Error(args ...interface{}) {
-	t.Log(args...)
-	t.Fail()
+	t.record("Error", args...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(lnEntry(EntryError, args))
+	t.fail()
 }
 
 // Errorf logs the given format and args with Logf(), and then calls Fail() to
 // mark the *T instance as failed.
 func (t *T) Errorf(format string, args ...interface{}) {
-	t.Logf(format, args...)
-	t.Fail()
+	t.record("Errorf", formatArgs(format, args)...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(fEntry(EntryError, format, args))
+	t.fail()
 }
 
 // Fail marks the *T instance as having failed. You can check if the *T instance
 // has been failed with Failed(), or how many times it has been failed with
 // FailedCount().
 func (t *T) Fail() {
+	t.record("Fail")
+	t.fail()
+}
+
+func (t *T) fail() {
+	t.mux.Lock()
 	t.failed++
+	t.mux.Unlock()
+
+	if t.wrapped != nil {
+		t.wrapped.Fail()
+	}
 }
 
 // FailNow marks the *T instance as having failed, and also aborts the current
 // goroutine with runtime.Goexit(). If the WithNoAbort() option was used when
 // initializing the *T instance, runtime.Goexit() will not be called.
 func (t *T) FailNow() {
-	t.Fail()
+	t.record("FailNow")
+	t.fail()
 	t.goexit()
 }
 
 // Failed returns true if the *T instance has been marked as failed.
 func (t *T) Failed() bool {
+	t.record("Failed")
+
+	if t.wrapped != nil {
+		return t.wrapped.Failed()
+	}
+
+	return t.isFailed()
+}
+
+func (t *T) isFailed() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
 	return t.failed > 0
 }
 
@@ -236,8 +567,11 @@ func (t *T) Failed() bool {
 //
 // See FailNow() and WithNoAbort() for details about how abort works.
 func (t *T) Fatal(args ...interface{}) {
-	t.Log(args...)
-	t.FailNow()
+	t.record("Fatal", args...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.fatal(lnEntry(EntryFatal, args))
 }
 
 // Fatalf logs the given format and args with Logf(), and then calls FailNow()
@@ -245,35 +579,170 @@ func (t *T) Fatal(args ...interface{}) {
 //
 // See FailNow() and WithNoAbort() for details about how abort works.
 func (t *T) Fatalf(format string, args ...interface{}) {
-	t.Logf(format, args...)
-	t.FailNow()
+	t.record("Fatalf", formatArgs(format, args)...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.fatal(fEntry(EntryFatal, format, args))
+}
+
+func (t *T) fatal(entry Entry) {
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+
+	t.log(entry)
+	t.fail()
+	t.goexit()
 }
 
 // Log renders given args to a string with fmt.Sprintln() and stores the result
 // in a string slice which can be accessed with Output().
+//
+// The file and line which *testing.T would prefix the output with is also
+// recorded, and can be accessed along with the output and the kind of method
+// which produced it via Entries().
 func (t *T) Log(args ...interface{}) {
-	t.mux.Lock()
-	defer t.mux.Unlock()
-
-	t.output = append(t.output, fmt.Sprintln(args...))
+	t.record("Log", args...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(lnEntry(EntryLog, args))
 }
 
 // Logf renders given format and args to a string with fmt.Sprintf() and stores
 // the result in a string slice which can be accessed with Output().
 func (t *T) Logf(format string, args ...interface{}) {
+	t.record("Logf", formatArgs(format, args)...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(fEntry(EntryLog, format, args))
+}
+
+func (t *T) log(entry Entry) {
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+
+	frames := callerFrames()
+	helpers := t.helperSet()
+	frame := attributedFrame(frames, helpers)
+
+	entry.Seq = nextSeq()
+	entry.File = frame.File
+	entry.Line = frame.Line
+
 	t.mux.Lock()
-	defer t.mux.Unlock()
+	if t.helperCheck {
+		t.addMissingHelpers(missingHelpers(frames, helpers))
+	}
+	t.output = append(t.output, entry.Text)
+	t.entries = append(t.entries, entry)
+	t.mux.Unlock()
 
+	if t.wrapped != nil {
+		t.forward(entry)
+	}
+	if t.tee != nil {
+		t.tee.forward(t.name, entry)
+	}
+}
+
+func (t *T) addMissingHelpers(names []string) {
+	for _, name := range names {
+		found := false
+		for _, m := range t.missing {
+			if m == name {
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			t.missing = append(t.missing, name)
+		}
+	}
+}
+
+// sprintf renders given format and args with fmt.Sprintf(), ensuring the
+// result ends with a newline like fmt.Sprintln() does.
+func sprintf(format string, args ...interface{}) string {
 	if len(format) == 0 || format[len(format)-1] != '\n' {
 		format += "\n"
 	}
-	t.output = append(t.output, fmt.Sprintf(format, args...))
+
+	return fmt.Sprintf(format, args...)
 }
 
 // Parallel marks the *T instance to indicate Parallel() has been called.
 // Use Paralleled() to check if Parallel() has been called.
+//
+// If the WithParallelSubtests() option was used, and the *T instance is a
+// sub-test created by Run(), Parallel() also pauses the sub-test until the
+// parent's test function has returned. Like *testing.T, it panics if called
+// more than once in this mode.
+//
+// Like *testing.T, it panics if the *T instance has modified the environment
+// via Setenv() due to the WithRealSetenv() option.
 func (t *T) Parallel() {
+	t.record("Parallel")
+
+	if t.denyParallel {
+		panic(parallelConflict)
+	}
+
+	if p, ok := t.wrapped.(parallelizer); ok {
+		t.setParallel()
+		t.setPauseSeq()
+		p.Parallel()
+		t.setContSeq()
+
+		return
+	}
+
+	if t.pauseC == nil {
+		t.setParallel()
+		t.setPauseSeq()
+		t.setContSeq()
+
+		return
+	}
+
+	if t.setParallel() {
+		panic("testing: t.Parallel called multiple times")
+	}
+
+	t.setPauseSeq()
+	close(t.pauseC)
+	<-t.resumeC
+	t.setContSeq()
+}
+
+// setParallel marks the *T instance as parallel, returning true if it already
+// was.
+func (t *T) setParallel() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	was := t.parallel
 	t.parallel = true
+
+	return was
+}
+
+func (t *T) setPauseSeq() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.pauseSeq = nextSeq()
+}
+
+func (t *T) setContSeq() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.contSeq = nextSeq()
 }
 
 // Skip logs the given args with Log(), and then uses SkipNow() to mark the *T
@@ -281,8 +750,12 @@ func (t *T) Parallel() {
 //
 // See SkipNow() for more details about aborting the current goroutine.
 func (t *T) Skip(args ...interface{}) {
-	t.Log(args...)
-	t.SkipNow()
+	t.record("Skip", args...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(lnEntry(EntrySkip, args))
+	t.skip()
 }
 
 // Skipf logs the given format and args with Logf(), and then uses SkipNow() to
@@ -290,22 +763,45 @@ func (t *T) Skip(args ...interface{}) {
 //
 // See SkipNow() for more details about aborting the current goroutine.
 func (t *T) Skipf(format string, args ...interface{}) {
-	t.Logf(format, args...)
-	t.SkipNow()
+	t.record("Skipf", formatArgs(format, args)...)
+	for _, tb := range t.helperTBs() {
+		tb.Helper()
+	}
+	t.log(fEntry(EntrySkip, format, args))
+	t.skip()
 }
 
 // SkipNow marks the *T instance as skipped, and then aborts the current
 // goroutine with runtime.Goexit(). If the WithNoAbort() option was used when
 // initializing the *T instance, runtime.Goexit() will not be called.
 func (t *T) SkipNow() {
+	t.record("SkipNow")
+	t.skip()
+}
+
+func (t *T) skip() {
+	t.mux.Lock()
 	t.skipped = true
+	t.mux.Unlock()
+
 	t.goexit()
 }
 
 // Skipped returns true if the *T instance has been marked as skipped, otherwise
-// it returns false.
+// it returns false. A *T returned by Wrap() also reports true if the wrapped
+// testing.TB has been skipped.
 func (t *T) Skipped() bool {
-	return t.skipped
+	t.record("Skipped")
+
+	t.mux.RLock()
+	skipped := t.skipped
+	t.mux.RUnlock()
+
+	if !skipped && t.wrapped != nil {
+		return t.wrapped.Skipped()
+	}
+
+	return skipped
 }
 
 // Helper marks the function that is calling Helper() as a helper function.
@@ -316,6 +812,8 @@ func (t *T) Skipped() bool {
 // include the absolute Go package path to the function, along with the function
 // name itself.
 func (t *T) Helper() {
+	t.record("Helper")
+
 	pc, _, _, ok := runtime.Caller(1)
 	if !ok {
 		return
@@ -329,14 +827,23 @@ func (t *T) Helper() {
 	t.helpers = append(t.helpers, fnName)
 }
 
-// Cleanup registers a cleanup function. *T does not run cleanup functions, it
-// simply records them for the purpose of later inspection via CleanupFuncs() or
-// CleanupNames().
+// Cleanup registers a cleanup function. Cleanup functions are recorded for the
+// purpose of later inspection via CleanupFuncs() or CleanupNames(), and are
+// executed when Finish() is called.
+//
+// Sub-tests created with Run() have Finish() called automatically once the
+// sub-test function returns.
 func (t *T) Cleanup(f func()) {
+	t.record("Cleanup", f)
+	t.cleanup(f)
+}
+
+func (t *T) cleanup(f func()) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
 	t.cleanups = append(t.cleanups, f)
+	t.pendingCleanups = append(t.pendingCleanups, f)
 }
 
 // TempDir creates an actual temporary directory on the system using
@@ -351,9 +858,14 @@ func (t *T) Cleanup(f func()) {
 // mocktesting. But it is created via ioutil.TempDir(), so the operating system
 // should eventually clean it up.
 //
+// For a *T returned by Wrap(), the directory is instead created and cleaned up
+// by calling TempDir() on the wrapped testing.TB.
+//
 // A string slice of temporary directory paths created by calls to TempDir() can
 // be accessed with TempDirs().
 func (t *T) TempDir() string {
+	t.record("TempDir")
+
 	// Allow setting MkdirTemp function for the purpose of testing mocktesting
 	// itself..
 	f := t.mkdirTempFunc
@@ -361,6 +873,12 @@ func (t *T) TempDir() string {
 		f = ioutil.TempDir
 	}
 
+	if t.wrapped != nil {
+		f = func(string, string) (string, error) {
+			return t.wrapped.TempDir(), nil
+		}
+	}
+
 	dir, err := f(t.baseTempdir, "go-mocktesting*")
 	if err != nil {
 		err = fmt.Errorf("TempDir() failed to create directory: %w", err)
@@ -384,13 +902,38 @@ func (t *T) TempDir() string {
 // in, rather than the gorouting which is executing Run().
 //
 // The sub-test function will receive a new instance of *T which is a sub-test,
-// which name and other attributes set accordingly.
+// which name and other attributes set accordingly. Once the sub-test function
+// returns, Finish() is called on the sub-test to run its cleanup functions.
 //
 // If any sub-test *T is marked as failed, the parent *T instance will also
 // be marked as failed.
 //
+// When the WithParallelSubtests() option is used, sub-tests which call
+// Parallel() cause Run() to return early. Their failures are propagated to the
+// parent once they have completed, as part of the parent's Finish().
+//
+// If the sub-test function panics, the panic is recovered and the sub-test is
+// marked as failed. The panic value and stack trace can be inspected on the
+// sub-test with PanicValue() and PanicStack().
+//
 // The list of sub-test *T instances can be accessed with Subtests().
 func (t *T) Run(name string, f func(testing.TB)) bool {
+	t.record("Run", name, f)
+
+	if r, ok := t.wrapped.(testingRunner); ok {
+		return t.runWrapped(r, name, f)
+	}
+
+	subtest := t.newSubtest(name)
+
+	return t.runSubtest(subtest, func() {
+		f(subtest)
+	})
+}
+
+// newSubtest creates a new sub-test *T instance with the given name, which
+// inherits all settings from t, and is recorded as one of its sub-tests.
+func (t *T) newSubtest(name string) *T {
 	name = t.newSubTestName(name)
 	fullname := name
 	if t.name != "" {
@@ -403,6 +946,19 @@ func (t *T) Run(name string, f func(testing.TB)) bool {
 	subtest.testingT = t.testingT
 	subtest.deadline = t.deadline
 	subtest.timeout = t.timeout
+	subtest.clock = t.clock
+	subtest.started = t.now()
+	subtest.pause = t.pause
+	subtest.helperCheck = t.helperCheck
+	subtest.latePanic = t.latePanic
+	subtest.ownerCheck = t.ownerCheck
+	subtest.realSetenv = t.realSetenv
+	subtest.realChdir = t.realChdir
+	subtest.benchN = t.benchN
+	subtest.watchdog = t.watchdog
+	subtest.tee = t.tee
+	subtest.parent = t
+	subtest.done = make(chan struct{})
 
 	if t.subtestNames == nil {
 		t.subtestNames = map[string]bool{}
@@ -411,17 +967,267 @@ func (t *T) Run(name string, f func(testing.TB)) bool {
 	t.mux.Lock()
 	t.subtests = append(t.subtests, subtest)
 	t.subtestNames[name] = true
+	if t.pause {
+		if t.barrier == nil {
+			t.barrier = make(chan struct{})
+		}
+		subtest.pauseC = make(chan struct{})
+		subtest.resumeC = t.barrier
+	}
 	t.mux.Unlock()
 
-	Go(func() {
-		f(subtest)
-	})
+	return subtest
+}
+
+// runSubtest executes f in a new goroutine as the test function of the given
+// sub-test, which must have been created by newSubtest(). It returns once the
+// sub-test is finished, has been paused by Parallel(), or has timed out.
+//
+// If f panics, the panic is recovered and the sub-test is marked as failed.
+func (t *T) runSubtest(subtest *T, f func()) bool {
+	go func() {
+		defer close(subtest.done)
+
+		Go(func() {
+			subtest.setOwner(goroutineID())
+			defer subtest.recoverPanic()
+			f()
+		})
+		subtest.Finish()
+	}()
+
+	timeoutC, stop := subtest.timeoutTimer()
+	defer stop()
+
+	select {
+	case <-subtest.done:
+	case <-timeoutC:
+		subtest.timeoutExpired()
+	case <-subtest.pauseC:
+		t.mux.Lock()
+		t.paused = append(t.paused, subtest)
+		t.mux.Unlock()
 
-	if subtest.Failed() {
-		t.Fail()
+		return !subtest.isFailed()
 	}
 
-	return !subtest.Failed()
+	if subtest.isFailed() {
+		t.fail()
+	}
+
+	return !subtest.isFailed()
+}
+
+// Finish marks the *T instance as finished, and runs all cleanup functions
+// registered with Cleanup() in last added, first called order, much like
+// *testing.T does once a test function has returned.
+//
+// If any sub-tests have been paused by Parallel() due to the
+// WithParallelSubtests() option, they are resumed and waited on before any
+// cleanup functions are run. The context returned by Context() is canceled
+// just before cleanup functions are run.
+//
+// Each cleanup function is executed in a separate blocking goroutine, so a
+// cleanup function calling FailNow() or SkipNow() does not prevent remaining
+// cleanup functions from running. Panics within cleanup functions are
+// recovered, mark the *T instance as failed, and can be inspected with
+// CleanupPanics().
+//
+// Cleanup functions registered by other cleanup functions are also executed,
+// immediately after the cleanup function which registered them returns, just
+// like *testing.T does.
+// Calling Finish() multiple times only runs cleanup functions which have not
+// already been run.
+//
+// Once Finish() has returned, the *T instance has completed, and calls to
+// methods like Log() and Fail() are recorded as late calls. See
+// WithLateCallPanic() and LateCalls() for details.
+func (t *T) Finish() {
+	t.resumeParallel()
+
+	t.mux.Lock()
+	t.finished = true
+	cancel := t.cancel
+	t.mux.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	for {
+		t.mux.Lock()
+		n := len(t.pendingCleanups)
+		if n == 0 {
+			t.mux.Unlock()
+
+			break
+		}
+		f := t.pendingCleanups[n-1]
+		t.pendingCleanups = t.pendingCleanups[:n-1]
+		t.mux.Unlock()
+
+		t.runCleanup(f)
+	}
+
+	t.mux.Lock()
+	t.ended = t.now()
+	t.endSeq = nextSeq()
+	t.completed = true
+	exp := t.expectations
+	t.mux.Unlock()
+
+	if exp != nil {
+		exp.verify()
+	}
+}
+
+func (t *T) resumeParallel() {
+	t.mux.Lock()
+	barrier := t.barrier
+	paused := t.paused
+	t.barrier = nil
+	t.paused = nil
+	t.mux.Unlock()
+
+	if barrier != nil {
+		close(barrier)
+	}
+
+	for _, subtest := range paused {
+		subtest.wait(subtest.done)
+		if subtest.isFailed() {
+			t.fail()
+		}
+	}
+}
+
+// Go runs the provided function in a new goroutine, and blocks until the
+// goroutine has exited, just like the package-level Go() function.
+//
+// When the WithTimeoutEnforcement() option is used, Go() also returns once the
+// deadline of the *T instance has passed, marking it as timed out.
+func (t *T) Go(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		prev := t.setOwner(goroutineID())
+		defer t.setOwner(prev)
+		f()
+	}()
+
+	t.wait(done)
+}
+
+// setOwner sets the goroutine running the test function of the *T instance,
+// and returns the previous one.
+func (t *T) setOwner(id uint64) uint64 {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	prev := t.owner
+	t.owner = id
+
+	return prev
+}
+
+// wait blocks until done is closed. When the WithTimeoutEnforcement() option
+// is used, it also returns once the deadline has passed, marking the *T
+// instance as timed out.
+func (t *T) wait(done <-chan struct{}) {
+	timeoutC, stop := t.timeoutTimer()
+	defer stop()
+
+	select {
+	case <-done:
+	case <-timeoutC:
+		t.timeoutExpired()
+	}
+}
+
+// timeoutTimer returns a channel which receives a value once the deadline has
+// passed, and a function to stop the underlying timer. If timeouts are not
+// enforced, the returned channel is nil, and hence never receives.
+func (t *T) timeoutTimer() (<-chan time.Time, func()) {
+	if !t.watchdog || !t.timeout {
+		return nil, func() {}
+	}
+
+	if t.clock != nil {
+		return t.clock.After(t.deadline.Sub(t.clock.Now())), func() {}
+	}
+
+	timer := time.NewTimer(time.Until(t.deadline))
+
+	return timer.C, func() { timer.Stop() }
+}
+
+// now returns the current time of the clock used by the *T instance.
+func (t *T) now() time.Time {
+	if t.clock == nil {
+		return time.Now()
+	}
+
+	return t.clock.Now()
+}
+
+// timeoutExpired marks the *T instance as timed out and failed, capturing the
+// stacks of all goroutines. Only the first dump captured is kept.
+func (t *T) timeoutExpired() {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	t.mux.Lock()
+	if !t.timedOut {
+		t.timedOut = true
+		t.timeoutDump = buf
+	}
+	t.mux.Unlock()
+
+	t.fail()
+}
+
+// recoverPanic must be deferred directly. It recovers from a panic, recording
+// the panic value and stack trace, and marks the *T instance as failed.
+//
+// As recover() returns nil when the goroutine is aborted with
+// runtime.Goexit(), aborts by FailNow() or SkipNow() are not affected.
+func (t *T) recoverPanic() {
+	p := recover()
+	if p == nil {
+		return
+	}
+
+	t.mux.Lock()
+	t.panicked = true
+	t.panicValue = p
+	t.panicStack = debug.Stack()
+	t.mux.Unlock()
+
+	t.fail()
+}
+
+func (t *T) runCleanup(f func()) {
+	t.Go(func() {
+		defer func() {
+			if p := recover(); p != nil {
+				t.mux.Lock()
+				t.cleanupPanics = append(t.cleanupPanics, p)
+				t.mux.Unlock()
+
+				t.fail()
+			}
+		}()
+
+		f()
+	})
 }
 
 func (t *T) newSubTestName(name string) string {
@@ -455,6 +1261,38 @@ func (t *T) Output() []string {
 	return t.output
 }
 
+// Entries returns a slice of all output produced by calls to Log(), Error(),
+// Fatal(), Skip() and their formatted variants. Each entry holds the kind of
+// method which produced it, the raw args, the rendered text, and the file and
+// line it is attributed to.
+func (t *T) Entries() []Entry {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.entries
+}
+
+// EntriesOf returns a slice of all entries of the given kinds, in the order
+// they were produced. For example, EntriesOf(EntryError, EntryFatal) returns
+// only failure messages.
+func (t *T) EntriesOf(kinds ...EntryKind) []Entry {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	var r []Entry
+	for _, e := range t.entries {
+		for _, k := range kinds {
+			if e.Kind == k {
+				r = append(r, e)
+
+				break
+			}
+		}
+	}
+
+	return r
+}
+
 // CleanupFuncs returns a slice of functions given to Cleanup().
 func (t *T) CleanupFuncs() []func() {
 	t.mux.RLock()
@@ -477,9 +1315,180 @@ func (t *T) CleanupNames() []string {
 	return r
 }
 
+// LateCalls returns a slice of calls to Log(), Error(), Fatal(), Skip(), their
+// formatted variants, Fail() and FailNow() which were made after the *T
+// instance completed, along with the stack trace of the goroutine which made
+// each call. See WithLateCallPanic() for details.
+func (t *T) LateCalls() []LateCall {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.lateCalls
+}
+
+// NonTestGoroutineCalls returns a slice of calls to FailNow(), SkipNow(),
+// Fatal(), Skip() and their formatted variants which were made from a goroutine
+// other than the one running the test function. See WithGoroutineCheck() for
+// details.
+func (t *T) NonTestGoroutineCalls() []Call {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.nonTestCalls
+}
+
+// Finished returns true if Finish() has been called.
+func (t *T) Finished() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.finished
+}
+
+// CleanupPanics returns a slice of values recovered from cleanup functions
+// which panicked while being run by Finish().
+func (t *T) CleanupPanics() []interface{} {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.cleanupPanics
+}
+
+// Panicked returns true if the test function of the *T instance panicked.
+// Only sub-tests started by Run(), and tests run by the Run() method of *M,
+// recover panics from their test function.
+func (t *T) Panicked() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.panicked
+}
+
+// PanicValue returns the value the test function of the *T instance panicked
+// with, or nil if it did not panic.
+func (t *T) PanicValue() interface{} {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.panicValue
+}
+
+// PanicStack returns the stack trace of the goroutine the test function of the
+// *T instance panicked in, or nil if it did not panic.
+func (t *T) PanicStack() []byte {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.panicStack
+}
+
+// Started returns the time at which the *T instance was created.
+func (t *T) Started() time.Time {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.started
+}
+
+// Ended returns the time at which Finish() last completed running cleanup
+// functions. Returns a zero time.Time if Finish() has not been called.
+func (t *T) Ended() time.Time {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.ended
+}
+
+// Duration returns the time between Started() and Ended(). Returns zero if
+// Finish() has not been called.
+func (t *T) Duration() time.Duration {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	if t.ended.IsZero() {
+		return 0
+	}
+
+	return t.ended.Sub(t.started)
+}
+
+// TimedOut returns true if the deadline passed while waiting on a test,
+// sub-test or cleanup function. It is only ever true when the
+// WithTimeoutEnforcement() option is used.
+func (t *T) TimedOut() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.timedOut
+}
+
+// TimeoutDump returns the stacks of all goroutines, as captured by
+// runtime.Stack() when the *T instance timed out. Returns nil if it has not
+// timed out.
+func (t *T) TimeoutDump() []byte {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.timeoutDump
+}
+
+// Calls returns a slice of all calls made to testing.TB methods on the *T
+// instance, in the order they were made.
+//
+// Methods which are implemented by calling other methods, like Error() calling
+// Log() and Fail(), are only recorded once under their own name.
+func (t *T) Calls() []Call {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.calls
+}
+
+// CallsTo returns a slice of all calls made to the named testing.TB method on
+// the *T instance, in the order they were made.
+func (t *T) CallsTo(method string) []Call {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	var r []Call
+	for _, c := range t.calls {
+		if c.Method == method {
+			r = append(r, c)
+		}
+	}
+
+	return r
+}
+
+// TestName returns the name of the *T instance just like Name() does, but
+// without recording a call.
+func (t *T) TestName() string {
+	return t.name
+}
+
+// Result returns the status *testing.T would report for the *T instance, one
+// of "FAIL", "SKIP" or "PASS". Unlike Failed() and Skipped(), it does not
+// record a call.
+func (t *T) Result() string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	switch {
+	case t.failed > 0:
+		return "FAIL"
+	case t.skipped:
+		return "SKIP"
+	default:
+		return "PASS"
+	}
+}
+
 // FailedCount returns the number of times the *T instance has been marked as
 // failed.
 func (t *T) FailedCount() int {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
 	return t.failed
 }
 
@@ -490,6 +1499,9 @@ func (t *T) FailedCount() int {
 // Because the test was still instructed to abort, which is a separate matter
 // than that *T was specifically set to not abort the current goroutine.
 func (t *T) Aborted() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
 	return t.aborted
 }
 
@@ -503,8 +1515,25 @@ func (t *T) HelperNames() []string {
 	return t.helpers
 }
 
+// MissingHelpers returns a list of function names which produced output via the
+// *T instance, either directly or by calling other functions, without having
+// called Helper(). Each function name is only listed once, in the order they
+// were first found.
+//
+// Detection of missing helpers is only performed if the WithHelperCheck()
+// option is used. Names are in the same format as HelperNames().
+func (t *T) MissingHelpers() []string {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.missing
+}
+
 // Paralleled returns true if Parallel() has been called.
 func (t *T) Paralleled() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
 	return t.parallel
 }
 
@@ -397,6 +397,30 @@ func ExampleT_Cleanup() {
 	// running cleanup1
 }
 
+func ExampleT_Finish() {
+	mt := mocktesting.NewT("TestMyCleanup")
+	mt.Cleanup(func() {
+		fmt.Println("running cleanup1")
+	})
+	mt.Cleanup(func() {
+		fmt.Println("running cleanup2")
+	})
+	mt.Run("sub", func(t testing.TB) {
+		t.Cleanup(func() {
+			fmt.Println("running sub-test cleanup")
+		})
+	})
+
+	mt.Finish()
+	fmt.Printf("Finished: %+v\n", mt.Finished())
+
+	// Output:
+	// running sub-test cleanup
+	// running cleanup2
+	// running cleanup1
+	// Finished: true
+}
+
 func ExampleT_Run() {
 	requireTrue := func(t testing.TB, v bool) {
 		if v != true {
@@ -518,3 +542,25 @@ func ExampleT_Run_nested() {
 	// Sub1-Sub3-Output:
 	//   - expected 4 to be greater than 5
 }
+
+func ExampleT_Calls() {
+	assertTrue := func(t testing.TB, v bool) {
+		t.Helper()
+
+		if v != true {
+			t.Errorf("expected %t to be true", v)
+		}
+	}
+
+	mt := mocktesting.NewT("TestMyBoolean")
+	assertTrue(mt, false)
+	for _, c := range mt.Calls() {
+		fmt.Printf("%s%v\n", c.Method, c.Args)
+	}
+	fmt.Printf("Errorf calls: %d\n", len(mt.CallsTo("Errorf")))
+
+	// Output:
+	// Helper[]
+	// Errorf[expected %t to be true false]
+	// Errorf calls: 1
+}
@@ -3,7 +3,43 @@
 
 package mocktesting
 
+import (
+	"os"
+)
+
+// setenver is implemented by testing.TB on Go 1.17 and later.
+type setenver interface {
+	Setenv(key string, value string)
+}
+
+// WithRealSetenv makes Setenv() set environment variables on the current
+// process with os.Setenv(), in addition to recording them. The original value
+// of each variable, or the fact that it was not set, is restored by cleanup
+// functions run by Finish().
+//
+// Like *testing.T, Setenv() will panic if the *T instance or any of its parents
+// have called Parallel(), and Parallel() will panic if called after Setenv().
+//
+// If this option is not used, Setenv() only records the given key and value,
+// which can be inspected with Getenv().
+func WithRealSetenv() Option {
+	return optionFunc(func(t *T) {
+		t.realSetenv = true
+	})
+}
+
+// Setenv records the given key and value, which can be inspected with
+// Getenv(). If the WithRealSetenv() option was used, the environment variable
+// is also set on the current process.
 func (t *T) Setenv(key string, value string) {
+	t.record("Setenv", key, value)
+
+	if s, ok := t.wrapped.(setenver); ok {
+		s.Setenv(key, value)
+	} else if t.realSetenv {
+		t.setenv(key, value)
+	}
+
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
@@ -29,3 +65,22 @@ func (t *T) Getenv() map[string]string {
 
 	return t.env
 }
+
+func (t *T) setenv(key string, value string) {
+	t.checkParallel()
+
+	prevValue, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.fatal(fEntry(EntryFatal,
+			"cannot set environment variable: %v", []interface{}{err},
+		))
+
+		return
+	}
+
+	if ok {
+		t.cleanup(func() { _ = os.Setenv(key, prevValue) })
+	} else {
+		t.cleanup(func() { _ = os.Unsetenv(key) })
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestT_Setenv(t *testing.T) {
@@ -69,6 +70,109 @@ func TestT_Setenv(t *testing.T) {
 	}
 }
 
+func TestWithRealSetenv(t *testing.T) {
+	mt := &T{}
+
+	WithRealSetenv().apply(mt)
+
+	assert.Equal(t, true, mt.realSetenv)
+}
+
+func TestT_Setenv_real(t *testing.T) {
+	const existingKey = "GO_MOCKTESTING_EXISTING"
+	const newKey = "GO_MOCKTESTING_NEW"
+
+	t.Setenv(existingKey, "original")
+	require.NoError(t, os.Unsetenv(newKey))
+
+	mt := NewT("TestSetenv", WithRealSetenv())
+	mt.Run("sub", func(t testing.TB) {
+		mt := t.(*T)
+		mt.Setenv(existingKey, "changed")
+		mt.Setenv(newKey, "new")
+
+		assert.Equal(t, "changed", os.Getenv(existingKey))
+		assert.Equal(t, "new", os.Getenv(newKey))
+		assert.Equal(t,
+			map[string]string{existingKey: "changed", newKey: "new"},
+			mt.Getenv(),
+		)
+	})
+
+	assert.Equal(t, "original", os.Getenv(existingKey))
+	_, ok := os.LookupEnv(newKey)
+	assert.False(t, ok, "%s should not be set", newKey)
+	assert.False(t, mt.Failed())
+}
+
+func TestT_Setenv_realEmptyKey(t *testing.T) {
+	mt := NewT("TestSetenv", WithRealSetenv())
+
+	mt.Run("sub", func(t testing.TB) {
+		t.(*T).Setenv("", "foo")
+	})
+
+	sub := mt.Subtests()[0]
+	assert.True(t, sub.Failed())
+	assert.True(t, sub.Aborted())
+	require.Len(t, sub.Output(), 1)
+	assert.Contains(t, sub.Output()[0], "cannot set environment variable")
+}
+
+func TestT_Setenv_realParallel(t *testing.T) {
+	const key = "GO_MOCKTESTING_PARALLEL"
+
+	tests := []struct {
+		name string
+		f    func(mt *T) interface{}
+	}{
+		{
+			name: "Setenv after Parallel",
+			f: func(mt *T) (p interface{}) {
+				defer func() { p = recover() }()
+				mt.Parallel()
+				mt.Setenv(key, "foo")
+
+				return nil
+			},
+		},
+		{
+			name: "Setenv with parallel parent",
+			f: func(mt *T) interface{} {
+				mt.Parallel()
+				mt.Run("sub", func(t testing.TB) {
+					t.(*T).Setenv(key, "foo")
+				})
+
+				return mt.Subtests()[0].PanicValue()
+			},
+		},
+		{
+			name: "Parallel after Setenv",
+			f: func(mt *T) (p interface{}) {
+				defer func() { p = recover() }()
+				mt.Setenv(key, "foo")
+				mt.Parallel()
+
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestSetenv", WithRealSetenv())
+			defer mt.Finish()
+
+			var p interface{}
+			Go(func() {
+				p = tt.f(mt)
+			})
+
+			assert.Equal(t, parallelConflict, p)
+		})
+	}
+}
+
 func TestT_Getenv(t *testing.T) {
 	type fields struct {
 		env map[string]string
@@ -180,3 +284,19 @@ func TestT_Run_Go116(t *testing.T) {
 		})
 	}
 }
+
+func TestWrap_Setenv(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb, WithRealSetenv())
+
+	mw.Setenv("GO_MOCKTESTING_WRAP_SETENV", "foo")
+
+	assert.Equal(t,
+		map[string]string{"GO_MOCKTESTING_WRAP_SETENV": "foo"}, tb.Getenv(),
+	)
+	assert.Equal(t,
+		map[string]string{"GO_MOCKTESTING_WRAP_SETENV": "foo"}, mw.Getenv(),
+	)
+	_, ok := os.LookupEnv("GO_MOCKTESTING_WRAP_SETENV")
+	assert.False(t, ok)
+}
@@ -0,0 +1,213 @@
+//go:build go1.24
+// +build go1.24
+
+package mocktesting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// WithRealChdir makes Chdir() change the working directory of the current
+// process with os.Chdir(), in addition to recording the directory. The original
+// working directory is restored by a cleanup function run by Finish().
+//
+// Like *testing.T, the PWD environment variable is also updated on platforms
+// which use it, Chdir() will panic if the *T instance or any of its parents
+// have called Parallel(), and Parallel() will panic if called after Chdir().
+//
+// If this option is not used, Chdir() only records the given directory, which
+// can be inspected with Chdirs().
+func WithRealChdir() Option {
+	return optionFunc(func(t *T) {
+		t.realChdir = true
+	})
+}
+
+// Chdir records the given directory, which can be inspected with Chdirs(). If
+// the WithRealChdir() option was used, the working directory of the current
+// process is also changed.
+func (t *T) Chdir(dir string) {
+	t.record("Chdir", dir)
+
+	if t.wrapped != nil {
+		t.wrapped.Chdir(dir)
+	} else if t.realChdir {
+		t.chdir(dir)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.chdirs = append(t.chdirs, dir)
+}
+
+func (t *T) chdir(dir string) {
+	t.checkParallel()
+
+	oldwd, err := os.Open(".")
+	if err != nil {
+		t.fatal(lnEntry(EntryFatal, []interface{}{err}))
+
+		return
+	}
+	if err = os.Chdir(dir); err != nil {
+		_ = oldwd.Close()
+		t.fatal(lnEntry(EntryFatal, []interface{}{err}))
+
+		return
+	}
+
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		// Windows and Plan 9 do not use the PWD variable.
+	default:
+		if !filepath.IsAbs(dir) {
+			dir, err = os.Getwd()
+			if err != nil {
+				_ = oldwd.Close()
+				t.fatal(lnEntry(EntryFatal, []interface{}{err}))
+
+				return
+			}
+		}
+		t.setenv("PWD", dir)
+	}
+
+	t.cleanup(func() {
+		err := oldwd.Chdir()
+		_ = oldwd.Close()
+		if err != nil {
+			panic("testing.Chdir: " + err.Error())
+		}
+	})
+}
+
+// Chdirs returns a string slice of directories given to Chdir().
+func (t *T) Chdirs() []string {
+	if t.chdirs == nil {
+		t.mux.Lock()
+		t.chdirs = []string{}
+		t.mux.Unlock()
+	}
+
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.chdirs
+}
+
+// Context returns a context which is canceled by Finish() just before cleanup
+// functions are run. If the *T instance has a deadline, as set by WithTimeout()
+// or WithDeadline(), the context is also bound by it, as measured by the clock
+// set with WithClock().
+//
+// The context of a sub-test created by Run() is derived from the context of its
+// parent.
+func (t *T) Context() context.Context {
+	t.record("Context")
+
+	return t.context()
+}
+
+func (t *T) context() context.Context {
+	parent := context.Background()
+	if t.wrapped != nil {
+		parent = t.wrapped.Context()
+	} else if t.parent != nil {
+		parent = t.parent.context()
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.ctx == nil {
+		switch {
+		case t.timeout && t.clock != nil:
+			t.ctx, t.cancel = newClockContext(parent, t.clock, t.deadline)
+		case t.timeout:
+			t.ctx, t.cancel = context.WithDeadline(parent, t.deadline)
+		default:
+			t.ctx, t.cancel = context.WithCancel(parent)
+		}
+
+		if t.finished {
+			t.cancel()
+		}
+	}
+
+	return t.ctx
+}
+
+// clockContext is a context bound by a deadline measured by a Clock, rather
+// than the system clock like context.WithDeadline().
+type clockContext struct {
+	parent   context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mux sync.Mutex
+	err error
+}
+
+func newClockContext(
+	parent context.Context,
+	clock Clock,
+	deadline time.Time,
+) (context.Context, context.CancelFunc) {
+	c := &clockContext{
+		parent:   parent,
+		deadline: deadline,
+		done:     make(chan struct{}),
+	}
+
+	timeoutC := clock.After(deadline.Sub(clock.Now()))
+	go func() {
+		select {
+		case <-timeoutC:
+			c.cancel(context.DeadlineExceeded)
+		case <-parent.Done():
+			c.cancel(parent.Err())
+		case <-c.done:
+		}
+	}()
+
+	return c, func() { c.cancel(context.Canceled) }
+}
+
+func (c *clockContext) cancel(err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
+
+func (c *clockContext) Deadline() (time.Time, bool) {
+	if d, ok := c.parent.Deadline(); ok && d.Before(c.deadline) {
+		return d, ok
+	}
+
+	return c.deadline, true
+}
+
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *clockContext) Err() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.err
+}
+
+func (c *clockContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,289 @@
+//go:build go1.24
+// +build go1.24
+
+package mocktesting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRealChdir(t *testing.T) {
+	mt := &T{}
+
+	WithRealChdir().apply(mt)
+
+	assert.Equal(t, true, mt.realChdir)
+}
+
+func TestT_Chdir(t *testing.T) {
+	type fields struct {
+		chdirs []string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		dir    string
+		want   []string
+	}{
+		{
+			name: "first call",
+			dir:  "/tmp/foo",
+			want: []string{"/tmp/foo"},
+		},
+		{
+			name:   "add to existing",
+			fields: fields{chdirs: []string{"/tmp/foo"}},
+			dir:    "bar",
+			want:   []string{"/tmp/foo", "bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			mt := &T{chdirs: tt.fields.chdirs}
+
+			mt.Chdir(tt.dir)
+
+			assert.Equal(t, tt.want, mt.chdirs)
+			got, err := os.Getwd()
+			require.NoError(t, err)
+			assert.Equal(t, wd, got)
+		})
+	}
+}
+
+func TestT_Chdir_real(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	t.Setenv("PWD", wd)
+
+	mt := NewT("TestChdir", WithRealChdir())
+	mt.Run("sub", func(t testing.TB) {
+		t.(*T).Chdir(dir)
+
+		got, err := os.Getwd()
+		assert.NoError(t, err)
+		assert.Equal(t, dir, got)
+		if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+			assert.Equal(t, dir, os.Getenv("PWD"))
+		}
+	})
+
+	assert.False(t, mt.Failed())
+	assert.Equal(t, []string{dir}, mt.Subtests()[0].Chdirs())
+	got, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, got)
+	assert.Equal(t, wd, os.Getenv("PWD"))
+}
+
+func TestT_Chdir_realNonexistent(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := filepath.Join(t.TempDir(), "nope")
+
+	mt := NewT("TestChdir", WithRealChdir())
+	mt.Run("sub", func(t testing.TB) {
+		t.(*T).Chdir(dir)
+	})
+
+	sub := mt.Subtests()[0]
+	assert.True(t, sub.Failed())
+	assert.True(t, sub.Aborted())
+	assert.Len(t, sub.Output(), 1)
+	got, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, got)
+}
+
+func TestT_Chdir_realParallel(t *testing.T) {
+	mt := NewT("TestChdir", WithRealChdir())
+	defer mt.Finish()
+
+	var p interface{}
+	Go(func() {
+		defer func() { p = recover() }()
+		mt.Parallel()
+		mt.Chdir(os.TempDir())
+	})
+
+	assert.Equal(t, parallelConflict, p)
+}
+
+func TestT_Chdirs(t *testing.T) {
+	type fields struct {
+		chdirs []string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []string
+	}{
+		{
+			name:   "nil",
+			fields: fields{chdirs: nil},
+			want:   []string{},
+		},
+		{
+			name:   "many dirs",
+			fields: fields{chdirs: []string{"/tmp/foo", "bar"}},
+			want:   []string{"/tmp/foo", "bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &T{chdirs: tt.fields.chdirs}
+
+			got := mt.Chdirs()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT_Context(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	mt := NewT("TestContext", WithDeadline(deadline))
+
+	ctx := mt.Context()
+
+	assert.NoError(t, ctx.Err())
+	assert.Same(t, ctx, mt.Context())
+	got, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, got)
+
+	var errInCleanup error
+	mt.Cleanup(func() { errInCleanup = ctx.Err() })
+	mt.Finish()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, context.Canceled, errInCleanup)
+}
+
+func TestT_Context_noTimeout(t *testing.T) {
+	mt := NewT("TestContext", WithTimeout(0))
+
+	ctx := mt.Context()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestT_Context_afterFinish(t *testing.T) {
+	mt := NewT("TestContext")
+	mt.Finish()
+
+	ctx := mt.Context()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestT_Context_subtests(t *testing.T) {
+	mt := NewT("TestContext")
+
+	var subCtx context.Context
+	var subErrInCleanup error
+	mt.Run("sub", func(t testing.TB) {
+		subCtx = t.(*T).Context()
+		t.Cleanup(func() { subErrInCleanup = subCtx.Err() })
+
+		assert.NoError(t, subCtx.Err())
+	})
+
+	assert.Equal(t, context.Canceled, subCtx.Err())
+	assert.Equal(t, context.Canceled, subErrInCleanup)
+	assert.NoError(t, mt.Context().Err())
+
+	var parentCanceled bool
+	mt.Run("parent canceled", func(t testing.TB) {
+		ctx := t.(*T).Context()
+		mt.cancel()
+		<-ctx.Done()
+		parentCanceled = true
+	})
+
+	assert.True(t, parentCanceled)
+}
+
+func TestT_Context_clock(t *testing.T) {
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestContext", WithClock(clock), WithTimeout(time.Minute))
+
+	ctx := mt.Context()
+
+	got, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), got)
+	assert.NoError(t, ctx.Err())
+
+	var subCtx context.Context
+	mt.Run("sub", func(t testing.TB) {
+		subCtx = t.(*T).Context()
+
+		clock.Advance(59 * time.Second)
+		assert.NoError(t, subCtx.Err())
+
+		clock.Advance(time.Second)
+		<-subCtx.Done()
+	})
+
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.Equal(t, context.DeadlineExceeded, subCtx.Err())
+	got, ok = subCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), got)
+}
+
+func TestT_Context_clockCanceled(t *testing.T) {
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestContext", WithClock(clock), WithTimeout(time.Minute))
+
+	ctx := mt.Context()
+	mt.Finish()
+	clock.Advance(time.Hour)
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestWrap_Chdir(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb, WithRealChdir())
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	mw.Chdir("/nope")
+
+	assert.Equal(t, []string{"/nope"}, tb.Chdirs())
+	assert.Equal(t, []string{"/nope"}, mw.Chdirs())
+	got, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, got)
+}
+
+func TestWrap_Context(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb)
+
+	ctx := mw.Context()
+	assert.Len(t, tb.CallsTo("Context"), 1)
+	assert.NoError(t, ctx.Err())
+
+	tb.Finish()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.True(t, mw.Finished())
+}
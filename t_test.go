@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -308,6 +309,137 @@ func TestWithDeadline(t *testing.T) {
 	}
 }
 
+func TestWithClock(t *testing.T) {
+	mt := &T{}
+	clock := NewFakeClock(fakeEpoch)
+
+	WithClock(clock).apply(mt)
+
+	assert.Equal(t, clock, mt.clock)
+}
+
+func TestNewT_clock(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      []Option
+		wantDeadline time.Time
+		wantTimeout  bool
+	}{
+		{
+			name:         "default timeout",
+			wantDeadline: fakeEpoch.Add(10 * time.Minute),
+			wantTimeout:  true,
+		},
+		{
+			name:         "WithTimeout before WithClock",
+			options:      []Option{WithTimeout(time.Minute)},
+			wantDeadline: fakeEpoch.Add(time.Minute),
+			wantTimeout:  true,
+		},
+		{
+			name: "WithDeadline",
+			options: []Option{
+				WithTimeout(time.Minute),
+				WithDeadline(fakeEpoch.Add(time.Hour)),
+			},
+			wantDeadline: fakeEpoch.Add(time.Hour),
+			wantTimeout:  true,
+		},
+		{
+			name: "WithTimeout after WithDeadline",
+			options: []Option{
+				WithDeadline(fakeEpoch.Add(time.Hour)),
+				WithTimeout(time.Minute),
+			},
+			wantDeadline: fakeEpoch.Add(time.Minute),
+			wantTimeout:  true,
+		},
+		{
+			name:        "no timeout",
+			options:     []Option{WithTimeout(0)},
+			wantTimeout: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(fakeEpoch)
+			options := append(tt.options, WithClock(clock))
+
+			mt := NewT("TestClock", options...)
+
+			deadline, ok := mt.Deadline()
+			assert.Equal(t, tt.wantDeadline, deadline)
+			assert.Equal(t, tt.wantTimeout, ok)
+			assert.Equal(t, fakeEpoch, mt.Started())
+		})
+	}
+}
+
+func TestT_clock(t *testing.T) {
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestClock", WithClock(clock))
+
+	clock.Advance(time.Second)
+	mt.Log("foo")
+
+	clock.Advance(time.Second)
+	mt.Run("sub", func(t testing.TB) {
+		clock.Advance(3 * time.Second)
+	})
+
+	clock.Advance(time.Second)
+	mt.Finish()
+
+	assert.Equal(t, fakeEpoch, mt.Started())
+	assert.Equal(t, fakeEpoch.Add(6*time.Second), mt.Ended())
+	assert.Equal(t, 6*time.Second, mt.Duration())
+
+	calls := mt.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, fakeEpoch.Add(1*time.Second), calls[0].Time)
+	assert.Equal(t, fakeEpoch.Add(2*time.Second), calls[1].Time)
+
+	require.Len(t, mt.Subtests(), 1)
+	sub := mt.Subtests()[0]
+	assert.Equal(t, fakeEpoch.Add(2*time.Second), sub.Started())
+	assert.Equal(t, fakeEpoch.Add(5*time.Second), sub.Ended())
+	assert.Equal(t, 3*time.Second, sub.Duration())
+	deadline, ok := sub.Deadline()
+	assert.Equal(t, fakeEpoch.Add(10*time.Minute), deadline)
+	assert.True(t, ok)
+}
+
+func TestT_Go_clockTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestClock",
+		WithClock(clock),
+		WithTimeout(time.Minute),
+		WithTimeoutEnforcement(),
+	)
+
+	mt.Go(func() {
+		clock.Advance(59 * time.Second)
+		mt.Log("not yet")
+		clock.Advance(time.Second)
+		<-hang
+	})
+
+	assert.True(t, mt.TimedOut())
+	assert.True(t, mt.Failed())
+	assert.Equal(t, []string{"not yet\n"}, mt.Output())
+}
+
+func TestWithTimeoutEnforcement(t *testing.T) {
+	mt := &T{}
+
+	WithTimeoutEnforcement().apply(mt)
+
+	assert.Equal(t, true, mt.watchdog)
+}
+
 func TestWithNoAbort(t *testing.T) {
 	mt := &T{abort: true}
 
@@ -316,6 +448,38 @@ func TestWithNoAbort(t *testing.T) {
 	assert.Equal(t, false, mt.abort)
 }
 
+func TestWithParallelSubtests(t *testing.T) {
+	mt := &T{}
+
+	WithParallelSubtests().apply(mt)
+
+	assert.Equal(t, true, mt.pause)
+}
+
+func TestWithHelperCheck(t *testing.T) {
+	mt := &T{}
+
+	WithHelperCheck().apply(mt)
+
+	assert.Equal(t, true, mt.helperCheck)
+}
+
+func TestWithLateCallPanic(t *testing.T) {
+	mt := &T{}
+
+	WithLateCallPanic().apply(mt)
+
+	assert.Equal(t, true, mt.latePanic)
+}
+
+func TestWithGoroutineCheck(t *testing.T) {
+	mt := &T{}
+
+	WithGoroutineCheck().apply(mt)
+
+	assert.Equal(t, true, mt.ownerCheck)
+}
+
 func TestWithBaseTempdir(t *testing.T) {
 	type args struct {
 		dir string
@@ -720,6 +884,80 @@ func TestT_Failed(t *testing.T) {
 	}
 }
 
+func TestT_Calls(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	cleanup := func() {}
+
+	mt := NewT("TestCalls")
+	var wantGoroutine uint64
+	var helperLine int
+	runInGoroutine(func() {
+		wantGoroutine = goroutineID()
+		func() {
+			mt.Helper()
+			_, _, helperLine, _ = runtime.Caller(0)
+			mt.Errorf("expected %d, got %d", 1, 2)
+		}()
+		mt.Log("hello", "world")
+		mt.Cleanup(cleanup)
+		_ = mt.Failed()
+		mt.Skip("skipping")
+		mt.Log("never called")
+	})
+
+	got := mt.Calls()
+
+	require.Len(t, got, 6)
+	methods := make([]string, 0, len(got))
+	for _, c := range got {
+		methods = append(methods, c.Method)
+	}
+	assert.Equal(t,
+		[]string{"Helper", "Errorf", "Log", "Cleanup", "Failed", "Skip"},
+		methods,
+	)
+
+	assert.Nil(t, got[0].Args)
+	assert.Equal(t,
+		[]interface{}{"expected %d, got %d", 1, 2}, got[1].Args,
+	)
+	assert.Equal(t, []interface{}{"hello", "world"}, got[2].Args)
+	require.Len(t, got[3].Args, 1)
+	assert.Equal(t,
+		reflect.ValueOf(cleanup).Pointer(),
+		reflect.ValueOf(got[3].Args[0]).Pointer(),
+	)
+	assert.Equal(t, []interface{}{"skipping"}, got[5].Args)
+
+	assert.Equal(t, helperLine-1, got[0].Line)
+	assert.Equal(t, helperLine+1, got[1].Line)
+
+	var lastSeq uint64
+	for _, c := range got {
+		assert.Equal(t, testFile, c.File)
+		assert.Equal(t, wantGoroutine, c.Goroutine)
+		assert.Greater(t, c.Seq, lastSeq)
+		assert.WithinDuration(t, time.Now(), c.Time, 5*time.Second)
+		lastSeq = c.Seq
+	}
+}
+
+func TestT_CallsTo(t *testing.T) {
+	mt := &T{}
+	mt.Log("one")
+	mt.Error("two")
+	mt.Log("three")
+
+	assert.Nil(t, mt.CallsTo("Fatal"))
+
+	got := mt.CallsTo("Log")
+
+	require.Len(t, got, 2)
+	assert.Equal(t, []interface{}{"one"}, got[0].Args)
+	assert.Equal(t, []interface{}{"three"}, got[1].Args)
+	assert.Less(t, got[0].Seq, got[1].Seq)
+}
+
 func TestT_FailedCount(t *testing.T) {
 	type fields struct {
 		failed int
@@ -1028,6 +1266,23 @@ func TestT_Parallel(t *testing.T) {
 	}
 }
 
+func TestT_Parallel_pause(t *testing.T) {
+	mt := NewT("TestParallel", WithParallelSubtests())
+
+	var p interface{}
+	mt.Run("twice", func(t testing.TB) {
+		mt := t.(*T)
+		mt.Parallel()
+		func() {
+			defer func() { p = recover() }()
+			mt.Parallel()
+		}()
+	})
+	mt.Finish()
+
+	assert.Equal(t, "testing: t.Parallel called multiple times", p)
+}
+
 func TestT_Paralleled(t *testing.T) {
 	type fields struct {
 		parallel bool
@@ -1441,7 +1696,7 @@ func TestT_TempDir(t *testing.T) {
 			}
 
 			if tt.wantTestingT != nil {
-				assert.Equal(t, tt.wantTestingT, mt.testingT)
+				assertEqualMocktestingT(t, tt.wantTestingT, mt.testingT.(*T))
 			}
 		})
 	}
@@ -2158,6 +2413,444 @@ func TestT_Run(t *testing.T) {
 	}
 }
 
+func TestT_Finish(t *testing.T) {
+	tests := []struct {
+		name       string
+		f          func(mt *T, calls *[]string)
+		wantCalls  []string
+		wantFailed int
+		wantPanics []interface{}
+	}{
+		{
+			name: "no cleanups",
+			f:    func(mt *T, calls *[]string) {},
+		},
+		{
+			name: "runs cleanups in reverse order",
+			f: func(mt *T, calls *[]string) {
+				mt.Cleanup(func() { *calls = append(*calls, "first") })
+				mt.Cleanup(func() { *calls = append(*calls, "second") })
+				mt.Cleanup(func() { *calls = append(*calls, "third") })
+			},
+			wantCalls: []string{"third", "second", "first"},
+		},
+		{
+			name: "cleanup which aborts",
+			f: func(mt *T, calls *[]string) {
+				mt.Cleanup(func() { *calls = append(*calls, "first") })
+				mt.Cleanup(func() {
+					*calls = append(*calls, "second")
+					mt.FailNow()
+					*calls = append(*calls, "after FailNow")
+				})
+				mt.Cleanup(func() { *calls = append(*calls, "third") })
+			},
+			wantCalls:  []string{"third", "second", "first"},
+			wantFailed: 1,
+		},
+		{
+			name: "cleanup which panics",
+			f: func(mt *T, calls *[]string) {
+				mt.Cleanup(func() { *calls = append(*calls, "first") })
+				mt.Cleanup(func() {
+					*calls = append(*calls, "second")
+					panic("oops")
+				})
+				mt.Cleanup(func() { *calls = append(*calls, "third") })
+			},
+			wantCalls:  []string{"third", "second", "first"},
+			wantFailed: 1,
+			wantPanics: []interface{}{"oops"},
+		},
+		{
+			name: "cleanup which registers cleanup",
+			f: func(mt *T, calls *[]string) {
+				mt.Cleanup(func() { *calls = append(*calls, "first") })
+				mt.Cleanup(func() {
+					*calls = append(*calls, "second")
+					mt.Cleanup(func() { *calls = append(*calls, "nested") })
+				})
+			},
+			wantCalls: []string{"second", "nested", "first"},
+		},
+		{
+			name: "subtest cleanups run before parent cleanups",
+			f: func(mt *T, calls *[]string) {
+				mt.Cleanup(func() { *calls = append(*calls, "parent") })
+				mt.Run("sub", func(t testing.TB) {
+					t.Cleanup(func() { *calls = append(*calls, "sub") })
+				})
+				*calls = append(*calls, "after Run")
+			},
+			wantCalls: []string{"sub", "after Run", "parent"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestFinish")
+			calls := []string{}
+			tt.f(mt, &calls)
+
+			mt.Finish()
+
+			if tt.wantCalls == nil {
+				tt.wantCalls = []string{}
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantFailed, mt.failed)
+			assert.Equal(t, tt.wantPanics, mt.cleanupPanics)
+			assert.True(t, mt.finished)
+
+			// Calling Finish() again must not re-run any cleanups.
+			mt.Finish()
+
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestT_Run_parallelSubtests(t *testing.T) {
+	var mux sync.Mutex
+	events := []string{}
+	record := func(s string) {
+		mux.Lock()
+		defer mux.Unlock()
+		events = append(events, s)
+	}
+	indexOf := func(s string) int {
+		for i, e := range events {
+			if e == s {
+				return i
+			}
+		}
+		require.FailNowf(t, "event not found", "event: %s", s)
+
+		return -1
+	}
+
+	mt := NewT("TestParallel", WithParallelSubtests())
+	mt.Cleanup(func() { record("parent cleanup") })
+
+	var groupOK, okA, okB, okC bool
+	groupOK = mt.Run("group", func(t testing.TB) {
+		gt := t.(*T)
+		gt.Cleanup(func() { record("group cleanup") })
+
+		okA = gt.Run("a", func(t testing.TB) {
+			t.Cleanup(func() { record("a cleanup") })
+			t.(*T).Parallel()
+			record("a resumed")
+			t.Error("a failed")
+		})
+		record("after a")
+
+		okB = gt.Run("b", func(t testing.TB) {
+			t.(*T).Parallel()
+			record("b resumed")
+		})
+		record("after b")
+	})
+	record("group done")
+
+	okC = mt.Run("c", func(t testing.TB) {
+		t.(*T).Parallel()
+		record("c resumed")
+	})
+	record("after c")
+
+	assert.False(t, groupOK)
+	assert.True(t, okA)
+	assert.True(t, okB)
+	assert.True(t, okC)
+
+	assert.Less(t, indexOf("after a"), indexOf("after b"))
+	assert.Less(t, indexOf("after b"), indexOf("a resumed"))
+	assert.Less(t, indexOf("after b"), indexOf("b resumed"))
+	assert.Less(t, indexOf("a resumed"), indexOf("a cleanup"))
+	assert.Less(t, indexOf("a cleanup"), indexOf("group cleanup"))
+	assert.Less(t, indexOf("b resumed"), indexOf("group cleanup"))
+	assert.Less(t, indexOf("group cleanup"), indexOf("group done"))
+	assert.Len(t, events, 8)
+
+	sub := mt.Subtests()
+	require.Len(t, sub, 2)
+	assert.True(t, sub[0].Failed())
+	assert.True(t, sub[0].Subtests()[0].Failed())
+	assert.False(t, sub[0].Subtests()[1].Failed())
+	assert.True(t, sub[0].Subtests()[0].Paralleled())
+	assert.True(t, sub[1].Paralleled())
+
+	// Sub-test "c" is paused until Finish is called on the top-level *T.
+	mt.Finish()
+
+	assert.Less(t, indexOf("after c"), indexOf("c resumed"))
+	assert.Less(t, indexOf("c resumed"), indexOf("parent cleanup"))
+	assert.Len(t, events, 10)
+	assert.True(t, mt.Failed())
+}
+
+func TestT_Run_panic(t *testing.T) {
+	tests := []struct {
+		name          string
+		f             func(testing.TB)
+		wantOK        bool
+		wantPanicked  bool
+		wantPanic     interface{}
+		wantSkipped   bool
+		wantAborted   bool
+		wantCleanedUp bool
+	}{
+		{
+			name:          "no panic",
+			f:             func(t testing.TB) {},
+			wantOK:        true,
+			wantCleanedUp: true,
+		},
+		{
+			name:          "panic with string",
+			f:             func(t testing.TB) { panic("oops") },
+			wantOK:        false,
+			wantPanicked:  true,
+			wantPanic:     "oops",
+			wantCleanedUp: true,
+		},
+		{
+			name:          "panic with error",
+			f:             func(t testing.TB) { panic(errors.New("nope")) },
+			wantOK:        false,
+			wantPanicked:  true,
+			wantPanic:     errors.New("nope"),
+			wantCleanedUp: true,
+		},
+		{
+			name:          "FailNow",
+			f:             func(t testing.TB) { t.FailNow() },
+			wantOK:        false,
+			wantAborted:   true,
+			wantCleanedUp: true,
+		},
+		{
+			name:          "SkipNow",
+			f:             func(t testing.TB) { t.SkipNow() },
+			wantOK:        true,
+			wantSkipped:   true,
+			wantAborted:   true,
+			wantCleanedUp: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestPanic")
+			cleanedUp := false
+
+			ok := mt.Run("sub", func(t testing.TB) {
+				t.Cleanup(func() { cleanedUp = true })
+				tt.f(t)
+			})
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, !tt.wantOK, mt.Failed())
+			assert.False(t, mt.Panicked())
+
+			require.Len(t, mt.Subtests(), 1)
+			sub := mt.Subtests()[0]
+			assert.Equal(t, !tt.wantOK, sub.Failed())
+			assert.Equal(t, tt.wantPanicked, sub.Panicked())
+			assert.Equal(t, tt.wantPanic, sub.PanicValue())
+			assert.Equal(t, tt.wantSkipped, sub.Skipped())
+			assert.Equal(t, tt.wantAborted, sub.Aborted())
+			assert.Equal(t, tt.wantCleanedUp, cleanedUp)
+			assert.True(t, sub.Finished())
+			if tt.wantPanicked {
+				assert.Contains(t, string(sub.PanicStack()),
+					"TestT_Run_panic",
+				)
+			} else {
+				assert.Nil(t, sub.PanicStack())
+			}
+		})
+	}
+}
+
+func TestT_Go(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	tests := []struct {
+		name         string
+		options      []Option
+		f            func(mt *T)
+		wantReached  bool
+		wantFailed   bool
+		wantTimedOut bool
+	}{
+		{
+			name:        "returns",
+			f:           func(mt *T) {},
+			wantReached: true,
+		},
+		{
+			name:       "FailNow",
+			f:          func(mt *T) { mt.FailNow() },
+			wantFailed: true,
+		},
+		{
+			name: "deadline not enforced",
+			options: []Option{
+				WithTimeout(10 * time.Millisecond),
+			},
+			f: func(mt *T) {
+				time.Sleep(50 * time.Millisecond)
+			},
+			wantReached: true,
+		},
+		{
+			name: "deadline enforced",
+			options: []Option{
+				WithTimeout(10 * time.Millisecond),
+				WithTimeoutEnforcement(),
+			},
+			f: func(mt *T) {
+				<-hang
+			},
+			wantFailed:   true,
+			wantTimedOut: true,
+		},
+		{
+			name: "no deadline",
+			options: []Option{
+				WithTimeout(0),
+				WithTimeoutEnforcement(),
+			},
+			f: func(mt *T) {
+				time.Sleep(10 * time.Millisecond)
+			},
+			wantReached: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestGo", tt.options...)
+			var mux sync.Mutex
+			reached := false
+
+			f := tt.f
+			mt.Go(func() {
+				f(mt)
+				mux.Lock()
+				reached = true
+				mux.Unlock()
+			})
+
+			mux.Lock()
+			assert.Equal(t, tt.wantReached, reached)
+			mux.Unlock()
+			assert.Equal(t, tt.wantFailed, mt.Failed())
+			assert.Equal(t, tt.wantTimedOut, mt.TimedOut())
+			if tt.wantTimedOut {
+				assert.Contains(t, string(mt.TimeoutDump()), "TestT_Go")
+			} else {
+				assert.Nil(t, mt.TimeoutDump())
+			}
+		})
+	}
+}
+
+func TestT_Run_timeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	mt := NewT("TestTimeout",
+		WithTimeout(20*time.Millisecond),
+		WithTimeoutEnforcement(),
+	)
+
+	ok := mt.Run("hung", func(t testing.TB) {
+		<-hang
+	})
+
+	assert.False(t, ok)
+	assert.True(t, mt.Failed())
+	assert.False(t, mt.TimedOut())
+
+	require.Len(t, mt.Subtests(), 1)
+	sub := mt.Subtests()[0]
+	assert.True(t, sub.Failed())
+	assert.True(t, sub.TimedOut())
+	assert.Contains(t, string(sub.TimeoutDump()), "TestT_Run_timeout")
+	assert.False(t, sub.Finished())
+}
+
+func TestT_Run_timeoutLateFailure(t *testing.T) {
+	done := make(chan struct{})
+
+	mt := NewT("TestTimeout",
+		WithTimeout(20*time.Millisecond),
+		WithTimeoutEnforcement(),
+	)
+
+	ok := mt.Run("slow", func(tb testing.TB) {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		tb.Error("too late")
+	})
+	assert.False(t, ok)
+
+	require.Len(t, mt.Subtests(), 1)
+	sub := mt.Subtests()[0]
+	assert.Equal(t, 1, sub.FailedCount())
+	assert.False(t, sub.Aborted())
+
+	<-done
+
+	assert.Equal(t, 2, sub.FailedCount())
+	assert.True(t, sub.Failed())
+}
+
+func TestT_Run_parallelSubtestsTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	mt := NewT("TestTimeout",
+		WithTimeout(20*time.Millisecond),
+		WithTimeoutEnforcement(),
+		WithParallelSubtests(),
+	)
+
+	ok := mt.Run("hung", func(t testing.TB) {
+		t.(*T).Parallel()
+		<-hang
+	})
+	assert.True(t, ok)
+
+	mt.Finish()
+
+	assert.True(t, mt.Failed())
+	require.Len(t, mt.Subtests(), 1)
+	assert.True(t, mt.Subtests()[0].TimedOut())
+	assert.True(t, mt.Finished())
+}
+
+func TestT_Finish_timeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	mt := NewT("TestTimeout",
+		WithTimeout(20*time.Millisecond),
+		WithTimeoutEnforcement(),
+	)
+	third := false
+	mt.Cleanup(func() { <-hang })
+	mt.Cleanup(func() { third = true })
+
+	mt.Finish()
+
+	assert.True(t, third)
+	assert.True(t, mt.Failed())
+	assert.True(t, mt.TimedOut())
+	assert.True(t, mt.Finished())
+}
+
 func TestT_Output(t *testing.T) {
 	type fields struct {
 		output []string
@@ -2199,6 +2892,133 @@ func TestT_Output(t *testing.T) {
 	}
 }
 
+func TestT_Entries(t *testing.T) {
+	type fields struct {
+		entries []Entry
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []Entry
+	}{
+		{
+			name:   "nil",
+			fields: fields{},
+			want:   nil,
+		},
+		{
+			name: "multiple items",
+			fields: fields{
+				entries: []Entry{
+					{Text: "oops: not found\n", File: "foo_test.go", Line: 4},
+					{Text: "bye\n", File: "bar_test.go", Line: 12},
+				},
+			},
+			want: []Entry{
+				{Text: "oops: not found\n", File: "foo_test.go", Line: 4},
+				{Text: "bye\n", File: "bar_test.go", Line: 12},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &T{entries: tt.fields.entries}
+
+			got := mt.Entries()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT_Entries_kinds(t *testing.T) {
+	mt := NewT("TestEntries", WithNoAbort())
+	err := errors.New("nope")
+
+	mt.Log("log", 1)
+	mt.Logf("logf %d", 2)
+	mt.Error("error", err)
+	mt.Errorf("errorf: %v", err)
+	mt.Fatal("fatal")
+	mt.Fatalf("fatalf %s", "!")
+	mt.Skip("skip")
+	mt.Skipf("skipf %t", true)
+
+	want := []Entry{
+		{Kind: EntryLog, Args: []interface{}{"log", 1}, Text: "log 1\n"},
+		{
+			Kind:   EntryLog,
+			Format: "logf %d",
+			Args:   []interface{}{2},
+			Text:   "logf 2\n",
+		},
+		{
+			Kind: EntryError,
+			Args: []interface{}{"error", err},
+			Text: "error nope\n",
+		},
+		{
+			Kind:   EntryError,
+			Format: "errorf: %v",
+			Args:   []interface{}{err},
+			Text:   "errorf: nope\n",
+		},
+		{Kind: EntryFatal, Args: []interface{}{"fatal"}, Text: "fatal\n"},
+		{
+			Kind:   EntryFatal,
+			Format: "fatalf %s",
+			Args:   []interface{}{"!"},
+			Text:   "fatalf !\n",
+		},
+		{Kind: EntrySkip, Args: []interface{}{"skip"}, Text: "skip\n"},
+		{
+			Kind:   EntrySkip,
+			Format: "skipf %t",
+			Args:   []interface{}{true},
+			Text:   "skipf true\n",
+		},
+	}
+
+	got := mt.Entries()
+	calls := mt.Calls()
+	require.Len(t, got, len(want))
+	require.Len(t, calls, len(want))
+	for i, e := range got {
+		assert.Greater(t, e.Seq, calls[i].Seq)
+		if i > 0 {
+			assert.Greater(t, calls[i].Seq, got[i-1].Seq)
+		}
+		assert.Equal(t, calls[i].File, e.File)
+		assert.Equal(t, calls[i].Line, e.Line)
+
+		e.Seq, e.File, e.Line = 0, "", 0
+		assert.Equal(t, want[i], e)
+	}
+}
+
+func TestT_EntriesOf(t *testing.T) {
+	mt := &T{entries: []Entry{
+		{Kind: EntryLog, Text: "one\n"},
+		{Kind: EntryError, Text: "two\n"},
+		{Kind: EntryLog, Text: "three\n"},
+		{Kind: EntryFatal, Text: "four\n"},
+	}}
+
+	assert.Nil(t, mt.EntriesOf())
+	assert.Nil(t, mt.EntriesOf(EntrySkip))
+	assert.Equal(t,
+		[]Entry{{Kind: EntryError, Text: "two\n"}},
+		mt.EntriesOf(EntryError),
+	)
+	assert.Equal(t,
+		[]Entry{
+			{Kind: EntryError, Text: "two\n"},
+			{Kind: EntryFatal, Text: "four\n"},
+		},
+		mt.EntriesOf(EntryFatal, EntryError),
+	)
+}
+
 func TestT_CleanupFuncs(t *testing.T) {
 	cleanup1 := func() {}
 	cleanup2 := func() {}
@@ -2314,6 +3134,351 @@ func TestT_CleanupNames(t *testing.T) {
 	}
 }
 
+func TestT_Finished(t *testing.T) {
+	mt := &T{}
+	assert.False(t, mt.Finished())
+
+	mt.finished = true
+	assert.True(t, mt.Finished())
+}
+
+func TestT_LateCalls(t *testing.T) {
+	mt := NewT("TestFoo")
+	mt.Cleanup(func() {
+		mt.Log("in cleanup")
+	})
+	mt.Log("before finish")
+
+	mt.Finish()
+	assert.Empty(t, mt.LateCalls())
+
+	mt.Log("after finish")
+	mt.Errorf("late %s", "error")
+	mt.Helper()
+	mt.Name()
+	Go(func() {
+		mt.FailNow()
+	})
+
+	late := mt.LateCalls()
+	require.Len(t, late, 3)
+	assert.Equal(t, "Log", late[0].Method)
+	assert.Equal(t, []interface{}{"after finish"}, late[0].Args)
+	assert.Equal(t, "Errorf", late[1].Method)
+	assert.Equal(t, "FailNow", late[2].Method)
+	assert.Contains(t, string(late[0].Stack), "TestT_LateCalls")
+	assert.Contains(t, string(late[2].Stack), "TestT_LateCalls.func")
+	assert.Equal(t, late[0].Seq, mt.CallsTo("Log")[2].Seq)
+}
+
+func TestT_LateCalls_subtest(t *testing.T) {
+	mt := NewT("TestFoo")
+	var subtest testing.TB
+	mt.Run("sub", func(t testing.TB) {
+		subtest = t
+		t.Log("in time")
+	})
+
+	subtest.Log("too late")
+
+	assert.Empty(t, mt.LateCalls())
+	late := subtest.(*T).LateCalls()
+	require.Len(t, late, 1)
+	assert.Equal(t, []interface{}{"too late"}, late[0].Args)
+}
+
+func TestT_LateCalls_panic(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(t *T)
+		want string
+	}{
+		{
+			name: "Log",
+			f:    func(t *T) { t.Log("hello", "world") },
+			want: "Log in goroutine after TestFoo/sub has completed: " +
+				"hello world",
+		},
+		{
+			name: "Errorf",
+			f:    func(t *T) { t.Errorf("%s is %d", "foo", 42) },
+			want: "Log in goroutine after TestFoo/sub has completed: " +
+				"foo is 42",
+		},
+		{
+			name: "Fail",
+			f:    func(t *T) { t.Fail() },
+			want: "Fail in goroutine after TestFoo/sub has completed",
+		},
+		{
+			name: "FailNow",
+			f:    func(t *T) { t.FailNow() },
+			want: "Fail in goroutine after TestFoo/sub has completed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestFoo", WithLateCallPanic())
+			var subtest *T
+			mt.Run("sub", func(t testing.TB) {
+				subtest = t.(*T)
+			})
+
+			assert.PanicsWithValue(t, tt.want, func() {
+				tt.f(subtest)
+			})
+			assert.Len(t, subtest.LateCalls(), 1)
+			assert.False(t, subtest.Failed())
+		})
+	}
+}
+
+func TestT_NonTestGoroutineCalls(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(t testing.TB)
+	}{
+		{name: "FailNow", f: func(t testing.TB) { t.FailNow() }},
+		{name: "SkipNow", f: func(t testing.TB) { t.SkipNow() }},
+		{name: "Fatal", f: func(t testing.TB) { t.Fatal("foo") }},
+		{name: "Fatalf", f: func(t testing.TB) { t.Fatalf("foo %d", 1) }},
+		{name: "Skip", f: func(t testing.TB) { t.Skip("foo") }},
+		{name: "Skipf", f: func(t testing.TB) { t.Skipf("foo %d", 1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestFoo")
+			var subtest *T
+			var finished bool
+
+			mt.Run("sub", func(t testing.TB) {
+				subtest = t.(*T)
+				Go(func() {
+					tt.f(t)
+				})
+				finished = true
+			})
+
+			assert.True(t, finished)
+			require.Len(t, subtest.NonTestGoroutineCalls(), 1)
+			assert.Equal(t,
+				tt.name, subtest.NonTestGoroutineCalls()[0].Method,
+			)
+			assert.Empty(t, mt.NonTestGoroutineCalls())
+		})
+	}
+}
+
+func TestT_NonTestGoroutineCalls_testGoroutine(t *testing.T) {
+	mt := NewT("TestFoo")
+	var subtest *T
+
+	mt.Run("sub", func(t testing.TB) {
+		subtest = t.(*T)
+		t.Log("ok")
+		t.FailNow()
+	})
+	mt.Go(func() {
+		mt.SkipNow()
+	})
+
+	assert.Empty(t, subtest.NonTestGoroutineCalls())
+	assert.Empty(t, mt.NonTestGoroutineCalls())
+}
+
+func TestT_NonTestGoroutineCalls_noOwner(t *testing.T) {
+	mt := NewT("TestFoo")
+
+	Go(func() {
+		mt.FailNow()
+	})
+
+	assert.Empty(t, mt.NonTestGoroutineCalls())
+}
+
+func TestT_NonTestGoroutineCalls_afterGo(t *testing.T) {
+	mt := NewT("TestFoo")
+
+	mt.Go(func() {})
+	Go(func() {
+		mt.FailNow()
+	})
+
+	assert.Empty(t, mt.NonTestGoroutineCalls())
+}
+
+func TestT_NonTestGoroutineCalls_packageGo(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter), WithGoroutineCheck())
+	var line int
+	var finished bool
+	helper := func(t *T) {
+		t.Helper()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			line = callerLine()
+			t.Fatal("oops")
+		}()
+		<-done
+	}
+
+	Go(func() {
+		helper(mt)
+		finished = true
+	})
+
+	assert.True(t, finished)
+	require.Len(t, mt.NonTestGoroutineCalls(), 1)
+	assert.Equal(t, "Fatal", mt.NonTestGoroutineCalls()[0].Method)
+	assert.Equal(t, []string{
+		fmt.Sprintf(
+			"mocktesting: call to Fatal() from a non-test goroutine at "+
+				"t_test.go:%d\n",
+			line,
+		),
+	}, reporter.Output())
+
+	// The goroutine started by Go() is no longer the test goroutine once it
+	// has exited.
+	assert.Equal(t, uint64(0), mt.owner)
+}
+
+func TestT_NonTestGoroutineCalls_packageGoTestGoroutine(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter), WithGoroutineCheck())
+
+	Go(func() {
+		mt.Log("first use binds the goroutine")
+		Go(func() {
+			mt.Log("nested")
+		})
+		mt.FailNow()
+	})
+
+	assert.Empty(t, mt.NonTestGoroutineCalls())
+	assert.Empty(t, reporter.Output())
+}
+
+func TestT_NonTestGoroutineCalls_noOwnerReport(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo",
+		WithTestingT(reporter), WithGoroutineCheck(), WithNoAbort(),
+	)
+
+	line := callerLine()
+	mt.FailNow()
+
+	assert.Empty(t, mt.NonTestGoroutineCalls())
+	assert.Equal(t, []string{
+		fmt.Sprintf(
+			"mocktesting: call to FailNow() at t_test.go:%d cannot be "+
+				"checked, as TestFoo has no test goroutine; run the test "+
+				"function with Go()\n",
+			line,
+		),
+	}, reporter.Output())
+}
+
+func TestT_NonTestGoroutineCalls_report(t *testing.T) {
+	reporter := NewT("reporter")
+	mt := NewT("TestFoo", WithTestingT(reporter), WithGoroutineCheck())
+	var line int
+
+	mt.Go(func() {
+		Go(func() {
+			line = callerLine()
+			mt.Fatal("oops")
+		})
+	})
+
+	assert.True(t, reporter.Failed())
+	assert.Equal(t, []string{
+		fmt.Sprintf(
+			"mocktesting: call to Fatal() from a non-test goroutine at "+
+				"t_test.go:%d\n",
+			line,
+		),
+	}, reporter.Output())
+	assert.Len(t, mt.NonTestGoroutineCalls(), 1)
+}
+
+func TestT_CleanupPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		panics []interface{}
+		want   []interface{}
+	}{
+		{
+			name: "nil",
+			want: nil,
+		},
+		{
+			name:   "one panic",
+			panics: []interface{}{"oops"},
+			want:   []interface{}{"oops"},
+		},
+		{
+			name:   "many panics",
+			panics: []interface{}{"oops", errors.New("nope")},
+			want:   []interface{}{"oops", errors.New("nope")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &T{cleanupPanics: tt.panics}
+
+			got := mt.CleanupPanics()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT_Panicked(t *testing.T) {
+	mt := &T{}
+	assert.False(t, mt.Panicked())
+	assert.Nil(t, mt.PanicValue())
+	assert.Nil(t, mt.PanicStack())
+
+	mt = &T{
+		panicked:   true,
+		panicValue: "oops",
+		panicStack: []byte("goroutine 1 [running]:"),
+	}
+	assert.True(t, mt.Panicked())
+	assert.Equal(t, "oops", mt.PanicValue())
+	assert.Equal(t, []byte("goroutine 1 [running]:"), mt.PanicStack())
+}
+
+func TestT_TimedOut(t *testing.T) {
+	mt := &T{}
+	assert.False(t, mt.TimedOut())
+	assert.Nil(t, mt.TimeoutDump())
+
+	mt = &T{
+		timedOut:    true,
+		timeoutDump: []byte("goroutine 1 [running]:"),
+	}
+	assert.True(t, mt.TimedOut())
+	assert.Equal(t, []byte("goroutine 1 [running]:"), mt.TimeoutDump())
+}
+
+func TestT_Duration(t *testing.T) {
+	mt := &T{}
+	assert.True(t, mt.Started().IsZero())
+	assert.True(t, mt.Ended().IsZero())
+	assert.Equal(t, time.Duration(0), mt.Duration())
+
+	mt = &T{started: fakeEpoch}
+	assert.Equal(t, fakeEpoch, mt.Started())
+	assert.Equal(t, time.Duration(0), mt.Duration())
+
+	mt = &T{started: fakeEpoch, ended: fakeEpoch.Add(time.Minute)}
+	assert.Equal(t, fakeEpoch.Add(time.Minute), mt.Ended())
+	assert.Equal(t, time.Minute, mt.Duration())
+}
+
 func TestT_HelperNames(t *testing.T) {
 	type fields struct {
 		helpers []string
@@ -2371,6 +3536,80 @@ func TestT_HelperNames(t *testing.T) {
 	}
 }
 
+func TestT_MissingHelpers(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing []string
+		want    []string
+	}{
+		{
+			name: "nil",
+			want: nil,
+		},
+		{
+			name: "multiple functions",
+			missing: []string{
+				"github.com/jimeh/go-mocktesting.TestT_MissingHelpers.func1",
+				"github.com/jimeh/go-mocktesting.TestT_MissingHelpers.func2",
+			},
+			want: []string{
+				"github.com/jimeh/go-mocktesting.TestT_MissingHelpers.func1",
+				"github.com/jimeh/go-mocktesting.TestT_MissingHelpers.func2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := &T{missing: tt.missing}
+
+			got := mt.MissingHelpers()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT_TestName(t *testing.T) {
+	mt := NewT("TestFoo bar")
+
+	assert.Equal(t, "TestFoo_bar", mt.TestName())
+	assert.Empty(t, mt.Calls())
+}
+
+func TestT_Result(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(t *T)
+		want string
+	}{
+		{name: "pass", f: func(t *T) {}, want: "PASS"},
+		{name: "fail", f: func(t *T) { t.Fail() }, want: "FAIL"},
+		{name: "skip", f: func(t *T) { t.SkipNow() }, want: "SKIP"},
+		{
+			name: "fail and skip",
+			f: func(t *T) {
+				t.Fail()
+				t.SkipNow()
+			},
+			want: "FAIL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := NewT("TestFoo")
+			Go(func() {
+				tt.f(mt)
+			})
+			calls := len(mt.Calls())
+
+			got := mt.Result()
+
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, mt.Calls(), calls)
+		})
+	}
+}
+
 func TestT_Aborted(t *testing.T) {
 	type fields struct {
 		aborted bool
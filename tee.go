@@ -0,0 +1,55 @@
+package mocktesting
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// outputTee forwards output produced by *T instances to real test output and
+// writers, as configured by WithOutputTo() and WithOutputWriter(). It is shared
+// between a *T instance and all of its sub-tests.
+type outputTee struct {
+	mux     sync.Mutex
+	tbs     []testing.TB
+	writers []io.Writer
+}
+
+// teeLine renders a line of output as "name: file:line: text", in a format
+// similar to that of *testing.T. Additional lines within text are indented.
+func teeLine(name string, entry Entry) string {
+	text := strings.TrimSuffix(entry.Text, "\n")
+	text = strings.ReplaceAll(text, "\n", "\n    ")
+
+	var b strings.Builder
+	if name != "" {
+		b.WriteString(name + ": ")
+	}
+	if entry.File != "" {
+		fmt.Fprintf(&b, "%s:%d: ", filepath.Base(entry.File), entry.Line)
+	}
+	b.WriteString(text + "\n")
+
+	return b.String()
+}
+
+// forward writes the given output entry of the named *T instance to all
+// configured targets.
+func (o *outputTee) forward(name string, entry Entry) {
+	line := teeLine(name, entry)
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	for _, tb := range o.tbs {
+		tb.Helper()
+		tb.Log(strings.TrimSuffix(line, "\n"))
+	}
+
+	for _, w := range o.writers {
+		_, _ = io.WriteString(w, line)
+	}
+}
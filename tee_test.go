@@ -0,0 +1,146 @@
+package mocktesting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOutputTo(t *testing.T) {
+	tb1 := &T{}
+	tb2 := &T{}
+	mt := &T{}
+
+	WithOutputTo(tb1).apply(mt)
+	WithOutputTo(tb2).apply(mt)
+
+	assert.Equal(t, []testing.TB{tb1, tb2}, mt.tee.tbs)
+	assert.Nil(t, mt.tee.writers)
+}
+
+func TestWithOutputWriter(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	mt := &T{}
+
+	WithOutputWriter(&buf1).apply(mt)
+	WithOutputWriter(&buf2).apply(mt)
+
+	assert.Equal(t, []io.Writer{&buf1, &buf2}, mt.tee.writers)
+	assert.Nil(t, mt.tee.tbs)
+}
+
+func Test_teeLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		tname string
+		entry Entry
+		want  string
+	}{
+		{
+			name:  "full",
+			tname: "TestFoo/bar",
+			entry: Entry{Text: "hello\n", File: "/src/foo_test.go", Line: 42},
+			want:  "TestFoo/bar: foo_test.go:42: hello\n",
+		},
+		{
+			name:  "no trailing newline",
+			tname: "TestFoo",
+			entry: Entry{Text: "hello", File: "/src/foo_test.go", Line: 42},
+			want:  "TestFoo: foo_test.go:42: hello\n",
+		},
+		{
+			name:  "multi-line",
+			tname: "TestFoo",
+			entry: Entry{Text: "a\nb\nc\n", File: "/src/foo_test.go", Line: 1},
+			want:  "TestFoo: foo_test.go:1: a\n    b\n    c\n",
+		},
+		{
+			name:  "no name",
+			entry: Entry{Text: "hello\n", File: "/src/foo_test.go", Line: 42},
+			want:  "foo_test.go:42: hello\n",
+		},
+		{
+			name:  "no location",
+			tname: "TestFoo",
+			entry: Entry{Text: "hello\n"},
+			want:  "TestFoo: hello\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := teeLine(tt.tname, tt.entry)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT_outputTee(t *testing.T) {
+	var buf bytes.Buffer
+	tb := NewT("TestReal")
+	mt := NewT("TestFoo",
+		WithOutputTo(tb),
+		WithOutputWriter(&buf),
+	)
+
+	_, file, line, _ := runtime.Caller(0)
+	mt.Log("one")
+	mt.Errorf("two: %d", 2)
+	mt.Run("sub", func(t testing.TB) {
+		t.Skip("three")
+	})
+	Go(func() { mt.Fatal("four\nfive") })
+
+	file = filepath.Base(file)
+	want := []string{
+		fmt.Sprintf("TestFoo: %s:%d: one\n", file, line+1),
+		fmt.Sprintf("TestFoo: %s:%d: two: 2\n", file, line+2),
+		fmt.Sprintf("TestFoo/sub: %s:%d: three\n", file, line+4),
+		fmt.Sprintf("TestFoo: %s:%d: four\n    five\n", file, line+6),
+	}
+	assert.Equal(t, strings.Join(want, ""), buf.String())
+
+	logs := tb.CallsTo("Log")
+	if assert.Len(t, logs, len(want)) {
+		for i, c := range logs {
+			assert.Equal(t,
+				[]interface{}{strings.TrimSuffix(want[i], "\n")}, c.Args,
+			)
+		}
+	}
+	assert.NotEmpty(t, tb.CallsTo("Helper"))
+	assert.False(t, tb.Failed())
+
+	assert.Equal(t, []string{"one\n", "two: 2\n", "four\nfive\n"}, mt.Output())
+	assert.Equal(t, []string{"three\n"}, mt.Subtests()[0].Output())
+}
+
+func TestT_outputTee_attribution(t *testing.T) {
+	tb := &attributingTB{}
+	mt := NewT("TestFoo", WithOutputTo(tb))
+
+	var lines [2]int
+	lines[0] = callerLine()
+	mt.Errorf("teed %d", 1)
+	mt.Run("sub", func(t testing.TB) {
+		lines[1] = callerLine()
+		t.Fatal("teed")
+	})
+
+	assert.Equal(t, []string{
+		fmt.Sprintf(
+			"tee_test.go:%d: TestFoo: tee_test.go:%d: teed 1",
+			lines[0], lines[0],
+		),
+		fmt.Sprintf(
+			"tee_test.go:%d: TestFoo/sub: tee_test.go:%d: teed",
+			lines[1], lines[1],
+		),
+	}, tb.lines)
+}
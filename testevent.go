@@ -0,0 +1,96 @@
+package mocktesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TestEvent is a single event of the kind emitted by "go test -json", as
+// documented by "go doc test2json".
+type TestEvent struct {
+	// Action is one of "run", "pause", "cont", "output", "pass", "fail" or
+	// "skip".
+	Action string
+
+	// Package is the package being tested. It is always empty in events
+	// returned by TestEvents(), and can be set by the caller if needed.
+	Package string `json:",omitempty"`
+
+	// Test is the name of the test the event is about.
+	Test string `json:",omitempty"`
+
+	// Elapsed is the duration of the test in seconds, and is only set for
+	// "pass", "fail" and "skip" events.
+	Elapsed *float64 `json:",omitempty"`
+
+	// Output is a line of output, and is only set for "output" events.
+	Output string `json:",omitempty"`
+}
+
+// TestEvents returns the events "go test -json" would emit for the *T instance
+// and its sub-tests, if the *T instance was a top-level test.
+//
+// Events are built from the same information as Transcript(true). Like
+// "go test -json", the result of each sub-test is reported as soon as it
+// completes, rather than nested within the result of its parent.
+func (t *T) TestEvents() []TestEvent {
+	var r []TestEvent
+	output := func(name string, s string) {
+		for _, line := range strings.SplitAfter(s, "\n") {
+			if line != "" {
+				r = append(r, TestEvent{
+					Action: "output",
+					Test:   name,
+					Output: line,
+				})
+			}
+		}
+	}
+
+	for _, e := range t.events() {
+		name := e.t.name
+
+		switch e.action {
+		case eventRun:
+			r = append(r, TestEvent{Action: "run", Test: name})
+			output(name, "=== RUN   "+name+"\n")
+		case eventPause:
+			output(name, "=== PAUSE "+name+"\n")
+			r = append(r, TestEvent{Action: "pause", Test: name})
+		case eventCont:
+			r = append(r, TestEvent{Action: "cont", Test: name})
+			output(name, "=== CONT  "+name+"\n")
+		case eventOutput:
+			output(name, decorate(e.entry))
+		case eventEnd:
+			result := e.t.Result()
+			dur := fmtDuration(e.t.Duration())
+			elapsed, _ := strconv.ParseFloat(strings.TrimSuffix(dur, "s"), 64)
+
+			output(name, fmt.Sprintf("--- %s: %s (%s)\n", result, name, dur))
+			r = append(r, TestEvent{
+				Action:  strings.ToLower(result),
+				Test:    name,
+				Elapsed: &elapsed,
+			})
+		}
+	}
+
+	return r
+}
+
+// WriteTestEvents writes the events returned by TestEvents() to w as a stream
+// of JSON objects, one per line, in the same format as "go test -json".
+func (t *T) WriteTestEvents(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, e := range t.TestEvents() {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package mocktesting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func elapsed(f float64) *float64 {
+	return &f
+}
+
+func TestT_TestEvents(t *testing.T) {
+	var lines [4]int
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestFoo", WithClock(clock), WithParallelSubtests())
+
+	lines[0] = callerLine()
+	mt.Log("starting")
+	mt.Run("pass", func(t testing.TB) {
+		clock.Advance(1234 * time.Millisecond)
+	})
+	mt.Run("par", func(t testing.TB) {
+		t.(*T).Parallel()
+		lines[1] = callerLine()
+		t.Error("par failed")
+	})
+	mt.Run("skip", func(t testing.TB) {
+		lines[2] = callerLine()
+		t.Skip("not today\nor tomorrow")
+	})
+	lines[3] = callerLine()
+	mt.Log("done")
+	mt.Finish()
+
+	loc := func(i int) string {
+		return fmt.Sprintf("testevent_test.go:%d", lines[i])
+	}
+
+	want := []TestEvent{
+		{Action: "run", Test: "TestFoo"},
+		{Action: "output", Test: "TestFoo", Output: "=== RUN   TestFoo\n"},
+		{
+			Action: "output",
+			Test:   "TestFoo",
+			Output: "    " + loc(0) + ": starting\n",
+		},
+		{Action: "run", Test: "TestFoo/pass"},
+		{
+			Action: "output",
+			Test:   "TestFoo/pass",
+			Output: "=== RUN   TestFoo/pass\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/pass",
+			Output: "--- PASS: TestFoo/pass (1.23s)\n",
+		},
+		{Action: "pass", Test: "TestFoo/pass", Elapsed: elapsed(1.23)},
+		{Action: "run", Test: "TestFoo/par"},
+		{
+			Action: "output",
+			Test:   "TestFoo/par",
+			Output: "=== RUN   TestFoo/par\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/par",
+			Output: "=== PAUSE TestFoo/par\n",
+		},
+		{Action: "pause", Test: "TestFoo/par"},
+		{Action: "run", Test: "TestFoo/skip"},
+		{
+			Action: "output",
+			Test:   "TestFoo/skip",
+			Output: "=== RUN   TestFoo/skip\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/skip",
+			Output: "    " + loc(2) + ": not today\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/skip",
+			Output: "        or tomorrow\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/skip",
+			Output: "--- SKIP: TestFoo/skip (0.00s)\n",
+		},
+		{Action: "skip", Test: "TestFoo/skip", Elapsed: elapsed(0)},
+		{
+			Action: "output",
+			Test:   "TestFoo",
+			Output: "    " + loc(3) + ": done\n",
+		},
+		{Action: "cont", Test: "TestFoo/par"},
+		{
+			Action: "output",
+			Test:   "TestFoo/par",
+			Output: "=== CONT  TestFoo/par\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/par",
+			Output: "    " + loc(1) + ": par failed\n",
+		},
+		{
+			Action: "output",
+			Test:   "TestFoo/par",
+			Output: "--- FAIL: TestFoo/par (0.00s)\n",
+		},
+		{Action: "fail", Test: "TestFoo/par", Elapsed: elapsed(0)},
+		{
+			Action: "output",
+			Test:   "TestFoo",
+			Output: "--- FAIL: TestFoo (1.23s)\n",
+		},
+		{Action: "fail", Test: "TestFoo", Elapsed: elapsed(1.23)},
+	}
+
+	got := mt.TestEvents()
+
+	assert.Equal(t, want, got)
+}
+
+func TestT_WriteTestEvents(t *testing.T) {
+	mt := NewT("TestFoo", WithClock(NewFakeClock(fakeEpoch)))
+	mt.Run("sub", func(t testing.TB) {})
+	mt.Finish()
+
+	var buf bytes.Buffer
+	err := mt.WriteTestEvents(&buf)
+
+	assert.NoError(t, err)
+	want := []string{
+		`{"Action":"run","Test":"TestFoo"}`,
+		`{"Action":"output","Test":"TestFoo","Output":"=== RUN   TestFoo\n"}`,
+		`{"Action":"run","Test":"TestFoo/sub"}`,
+		`{"Action":"output","Test":"TestFoo/sub",` +
+			`"Output":"=== RUN   TestFoo/sub\n"}`,
+		`{"Action":"output","Test":"TestFoo/sub",` +
+			`"Output":"--- PASS: TestFoo/sub (0.00s)\n"}`,
+		`{"Action":"pass","Test":"TestFoo/sub","Elapsed":0}`,
+		`{"Action":"output","Test":"TestFoo",` +
+			`"Output":"--- PASS: TestFoo (0.00s)\n"}`,
+		`{"Action":"pass","Test":"TestFoo","Elapsed":0}`,
+	}
+	assert.Equal(t, strings.Join(want, "\n")+"\n", buf.String())
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestT_WriteTestEvents_error(t *testing.T) {
+	mt := NewT("TestFoo")
+
+	err := mt.WriteTestEvents(errWriter{})
+
+	assert.EqualError(t, err, "write failed")
+}
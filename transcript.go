@@ -0,0 +1,163 @@
+package mocktesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transcript renders the *T instance and its sub-tests as text, the way "go
+// test" would print them if the *T instance was a top-level test. Output from
+// Log(), Error() and friends is prefixed with the base name of the file and the
+// line it is attributed to.
+//
+// When verbose is true, the output matches "go test -v": "=== RUN",
+// "=== PAUSE" and "=== CONT" lines are printed as tests start, pause and
+// continue, output is printed as it happens, and a "--- PASS", "--- SKIP" or
+// "--- FAIL" line is printed for each test, with the results of sub-tests
+// nested within those of their parents.
+//
+// When verbose is false, only failed tests are reported, each followed by the
+// output it produced, like "go test" without the -v flag.
+//
+// Events are ordered by when they happened. As *T instances record their
+// duration based on the time Finish() was called, a *T instance which has not
+// been finished is reported with a duration of zero, after everything else.
+func (t *T) Transcript(verbose bool) string {
+	r := &transcript{
+		verbose: verbose,
+		root:    t,
+		output:  map[*T]*strings.Builder{},
+	}
+
+	for _, e := range t.events() {
+		r.handle(e)
+	}
+
+	return r.w.String()
+}
+
+// transcript renders events in the same way as the chatty printer of the
+// testing package.
+type transcript struct {
+	verbose  bool
+	root     *T
+	w        strings.Builder
+	lastName string
+
+	// output buffers the output of each test, which is printed after the
+	// test's "--- FAIL" line when it is reported.
+	output map[*T]*strings.Builder
+}
+
+func (r *transcript) handle(e event) {
+	name := e.t.name
+
+	switch e.action {
+	case eventRun:
+		if r.verbose {
+			r.updatef(name, "=== RUN   %s\n", name)
+		}
+	case eventPause:
+		if r.verbose {
+			r.updatef(name, "=== PAUSE %s\n", name)
+		}
+	case eventCont:
+		if r.verbose {
+			r.updatef(name, "=== CONT  %s\n", name)
+		}
+	case eventOutput:
+		line := decorate(e.entry)
+		if r.verbose {
+			r.printf(name, "%s", line)
+		} else {
+			r.buffer(e.t).WriteString(line)
+		}
+	case eventEnd:
+		r.report(e.t)
+	}
+}
+
+// report renders the result of a test, like the report method of *testing.T.
+func (r *transcript) report(t *T) {
+	result := t.Result()
+	output := r.buffer(t).String()
+	delete(r.output, t)
+
+	if result != "FAIL" && !r.verbose {
+		return
+	}
+
+	line := fmt.Sprintf(
+		"--- %s: %s (%s)\n", result, t.name, fmtDuration(t.Duration()),
+	)
+
+	if t == r.root || t.parent == nil {
+		r.updatef(t.name, "%s%s", line, output)
+
+		return
+	}
+
+	r.buffer(t.parent).WriteString(indentLines(line + output))
+}
+
+func (r *transcript) buffer(t *T) *strings.Builder {
+	b, ok := r.output[t]
+	if !ok {
+		b = &strings.Builder{}
+		r.output[t] = b
+	}
+
+	return b
+}
+
+// updatef prints a message which mentions the name of the test it is about.
+func (r *transcript) updatef(name string, format string, args ...interface{}) {
+	r.lastName = name
+	fmt.Fprintf(&r.w, format, args...)
+}
+
+// printf prints a message produced by the named test, preceded by a "=== NAME"
+// line if the previous message was about a different test.
+func (r *transcript) printf(name string, format string, args ...interface{}) {
+	if r.lastName == "" {
+		r.lastName = name
+	} else if r.lastName != name {
+		fmt.Fprintf(&r.w, "=== NAME  %s\n", name)
+		r.lastName = name
+	}
+	fmt.Fprintf(&r.w, format, args...)
+}
+
+// decorate renders an output entry the way *testing.T prints log output,
+// prefixed with its location and indented, with subsequent lines indented
+// further.
+func decorate(e Entry) string {
+	s := strings.TrimSuffix(e.Text, "\n")
+	s = strings.ReplaceAll(s, "\n", "\n        ")
+
+	if e.File != "" {
+		s = fmt.Sprintf("%s:%d: %s", filepath.Base(e.File), e.Line, s)
+	}
+
+	return "    " + s + "\n"
+}
+
+// indentLines indents each line of s by four spaces.
+func indentLines(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		if line != "" {
+			b.WriteString("    " + line)
+		}
+	}
+
+	return b.String()
+}
+
+// fmtDuration returns a string representing d in the form "87.00s".
+func fmtDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -0,0 +1,197 @@
+package mocktesting
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestT_Transcript(t *testing.T) {
+	var lines [6]int
+	clock := NewFakeClock(fakeEpoch)
+	mt := NewT("TestFoo", WithClock(clock), WithParallelSubtests())
+
+	lines[0] = callerLine()
+	mt.Log("starting")
+	mt.Run("pass", func(t testing.TB) {
+		lines[1] = callerLine()
+		t.Log("in pass")
+		clock.Advance(1500 * time.Millisecond)
+	})
+	mt.Run("skip", func(t testing.TB) {
+		lines[2] = callerLine()
+		t.Skip("not today")
+	})
+	mt.Run("par", func(t testing.TB) {
+		t.(*T).Parallel()
+		lines[3] = callerLine()
+		t.Error("par failed")
+	})
+	mt.Run("fail", func(t testing.TB) {
+		t.(*T).Run("inner", func(t testing.TB) {
+			lines[4] = callerLine()
+			t.Fatal("inner failed\nsecond line")
+		})
+	})
+	lines[5] = callerLine()
+	mt.Log("done")
+	mt.Finish()
+
+	loc := func(i int) string {
+		return fmt.Sprintf("transcript_test.go:%d", lines[i])
+	}
+
+	tests := []struct {
+		name    string
+		verbose bool
+		want    []string
+	}{
+		{
+			name:    "verbose",
+			verbose: true,
+			want: []string{
+				"=== RUN   TestFoo",
+				"    " + loc(0) + ": starting",
+				"=== RUN   TestFoo/pass",
+				"    " + loc(1) + ": in pass",
+				"=== RUN   TestFoo/skip",
+				"    " + loc(2) + ": not today",
+				"=== RUN   TestFoo/par",
+				"=== PAUSE TestFoo/par",
+				"=== RUN   TestFoo/fail",
+				"=== RUN   TestFoo/fail/inner",
+				"    " + loc(4) + ": inner failed",
+				"        second line",
+				"=== NAME  TestFoo",
+				"    " + loc(5) + ": done",
+				"=== CONT  TestFoo/par",
+				"    " + loc(3) + ": par failed",
+				"--- FAIL: TestFoo (1.50s)",
+				"    --- PASS: TestFoo/pass (1.50s)",
+				"    --- SKIP: TestFoo/skip (0.00s)",
+				"    --- FAIL: TestFoo/fail (0.00s)",
+				"        --- FAIL: TestFoo/fail/inner (0.00s)",
+				"    --- FAIL: TestFoo/par (0.00s)",
+			},
+		},
+		{
+			name:    "not verbose",
+			verbose: false,
+			want: []string{
+				"--- FAIL: TestFoo (1.50s)",
+				"    " + loc(0) + ": starting",
+				"    --- FAIL: TestFoo/fail (0.00s)",
+				"        --- FAIL: TestFoo/fail/inner (0.00s)",
+				"            " + loc(4) + ": inner failed",
+				"                second line",
+				"    " + loc(5) + ": done",
+				"    --- FAIL: TestFoo/par (0.00s)",
+				"        " + loc(3) + ": par failed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mt.Transcript(tt.verbose)
+
+			assert.Equal(t, strings.Join(tt.want, "\n")+"\n", got)
+		})
+	}
+}
+
+func TestT_Transcript_fields(t *testing.T) {
+	root := &T{name: "TestBar", runSeq: 1}
+	skipped := &T{
+		name:    "TestBar/skipped",
+		parent:  root,
+		skipped: true,
+		runSeq:  2,
+		endSeq:  4,
+		entries: []Entry{{Seq: 3, Text: "nope\n"}},
+	}
+	failed := &T{
+		name:    "TestBar/failed",
+		parent:  root,
+		failed:  1,
+		runSeq:  5,
+		endSeq:  7,
+		started: fakeEpoch,
+		ended:   fakeEpoch.Add(2 * time.Second),
+		entries: []Entry{
+			{Seq: 6, Text: "oops\n", File: "/src/bar_test.go", Line: 7},
+		},
+	}
+	unfinished := &T{
+		name:    "TestBar/unfinished",
+		parent:  root,
+		runSeq:  8,
+		entries: []Entry{{Seq: 9, Text: "hi\n", File: "bar_test.go", Line: 3}},
+	}
+	root.subtests = []*T{skipped, failed, unfinished}
+	root.failed = 1
+
+	tests := []struct {
+		name    string
+		t       *T
+		verbose bool
+		want    []string
+	}{
+		{
+			name:    "verbose",
+			t:       root,
+			verbose: true,
+			want: []string{
+				"=== RUN   TestBar",
+				"=== RUN   TestBar/skipped",
+				"    nope",
+				"=== RUN   TestBar/failed",
+				"    bar_test.go:7: oops",
+				"=== RUN   TestBar/unfinished",
+				"    bar_test.go:3: hi",
+				"--- FAIL: TestBar (0.00s)",
+				"    --- SKIP: TestBar/skipped (0.00s)",
+				"    --- FAIL: TestBar/failed (2.00s)",
+				"    --- PASS: TestBar/unfinished (0.00s)",
+			},
+		},
+		{
+			name: "not verbose",
+			t:    root,
+			want: []string{
+				"--- FAIL: TestBar (0.00s)",
+				"    --- FAIL: TestBar/failed (2.00s)",
+				"        bar_test.go:7: oops",
+			},
+		},
+		{
+			name:    "sub-test as root",
+			t:       failed,
+			verbose: true,
+			want: []string{
+				"=== RUN   TestBar/failed",
+				"    bar_test.go:7: oops",
+				"--- FAIL: TestBar/failed (2.00s)",
+			},
+		},
+		{
+			name:    "passing not verbose",
+			t:       unfinished,
+			verbose: false,
+			want:    []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.t.Transcript(tt.verbose)
+
+			want := strings.Join(tt.want, "\n")
+			if want != "" {
+				want += "\n"
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package mocktesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testingRunner is implemented by *testing.T.
+type testingRunner interface {
+	Run(name string, f func(t *testing.T)) bool
+}
+
+// deadliner is implemented by *testing.T.
+type deadliner interface {
+	Deadline() (time.Time, bool)
+}
+
+// parallelizer is implemented by *testing.T.
+type parallelizer interface {
+	Parallel()
+}
+
+// Wrap returns a *T which wraps the given testing.TB, typically a real
+// *testing.T. Like a *T returned by NewT(), all calls are recorded and can be
+// inspected, but they are also forwarded to tb, so the real test fails, skips
+// and logs as the helper under test instructs it to.
+//
+// The name and deadline of the *T instance are taken from tb. Cleanup
+// functions are run by Finish(), which is registered as a cleanup function on
+// tb. TempDir(), Setenv(), Chdir() and Context() are delegated to tb, and
+// Parallel() and Run() are too when tb supports them, as *testing.T does.
+// Sub-tests started by Run() are then real sub-tests of tb, wrapped by a *T
+// instance of their own.
+//
+// As tb can only be skipped by aborting the current goroutine, SkipNow() is
+// not forwarded to tb when the WithNoAbort() option is used. Skipped() and
+// Result() still report the *T instance itself as skipped.
+//
+// Recorded output is attributed to the same location as it would be by a *T
+// returned by NewT(), honoring calls to Helper() on the *T instance. Output is
+// forwarded with tb.Log(), prefixed with that file and line, so helpers under
+// test which called Helper() are skipped in the real test output too. All
+// functions within mocktesting are marked as helpers on tb, so the location
+// tb itself reports is that of the call to the *T instance's method.
+func Wrap(tb testing.TB, options ...Option) *T {
+	t := NewT(tb.Name(), options...)
+	t.wrap(tb)
+	tb.Cleanup(t.Finish)
+
+	return t
+}
+
+func (t *T) wrap(tb testing.TB) {
+	t.name = tb.Name()
+	t.wrapped = tb
+	t.owner = goroutineID()
+	if t.testingT == nil {
+		t.testingT = tb
+	}
+	if d, ok := tb.(deadliner); ok {
+		t.deadline, t.timeout = d.Deadline()
+	}
+}
+
+// forward sends the given output entry to the wrapped testing.TB, prefixed
+// with the file and line the entry is attributed to.
+func (t *T) forward(entry Entry) {
+	t.wrapped.Helper()
+
+	text := strings.TrimSuffix(entry.Text, "\n")
+	if entry.File != "" {
+		text = fmt.Sprintf(
+			"%s:%d: %s", filepath.Base(entry.File), entry.Line, text,
+		)
+	}
+	t.wrapped.Log(text)
+}
+
+// helperTBs returns the real testing.TB instances which output is forwarded
+// to, by Wrap() or WithOutputTo(). Every function on the path from an exported
+// method to forwarding output calls Helper() on them, so the real test output
+// is attributed to the caller of the exported method, rather than to
+// mocktesting itself.
+func (t *T) helperTBs() []testing.TB {
+	var r []testing.TB
+	if t.wrapped != nil {
+		r = append(r, t.wrapped)
+	}
+	if t.tee != nil {
+		t.tee.mux.Lock()
+		r = append(r, t.tee.tbs...)
+		t.tee.mux.Unlock()
+	}
+
+	return r
+}
+
+// runWrapped runs f as a real sub-test of the wrapped testing.TB, passing it a
+// *T which wraps the real sub-test.
+func (t *T) runWrapped(r testingRunner, name string, f func(testing.TB)) bool {
+	return r.Run(name, func(rt *testing.T) {
+		subtest := t.newSubtest(name)
+		subtest.wrap(rt)
+		rt.Cleanup(subtest.Finish)
+
+		f(subtest)
+	})
+}
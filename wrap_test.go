@@ -0,0 +1,370 @@
+package mocktesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	tb := NewT("TestOuter", WithDeadline(fakeEpoch))
+
+	mw := Wrap(tb, WithNoAbort())
+
+	assert.Equal(t, "TestOuter", mw.Name())
+	assert.Equal(t, tb, mw.wrapped)
+	assert.Equal(t, tb, mw.testingT)
+	assert.False(t, mw.abort)
+	deadline, ok := mw.Deadline()
+	assert.Equal(t, fakeEpoch, deadline)
+	assert.True(t, ok)
+	assert.Len(t, tb.CleanupFuncs(), 1)
+}
+
+func TestWrap_noAbortSkip(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb, WithNoAbort())
+	reached := false
+
+	Go(func() {
+		mw.Skip("foo")
+		reached = true
+	})
+
+	assert.True(t, reached)
+	assert.False(t, tb.skipped)
+	assert.False(t, tb.Aborted())
+	assert.True(t, mw.Skipped())
+	assert.True(t, mw.Aborted())
+	assert.Equal(t, "SKIP", mw.Result())
+}
+
+func TestWrap_forwarding(t *testing.T) {
+	tests := []struct {
+		name        string
+		f           func(mw *T)
+		wantCalls   []string
+		wantOutput  []string
+		wantFailed  bool
+		wantSkipped bool
+		wantAborted bool
+	}{
+		{
+			name:       "Log",
+			f:          func(mw *T) { mw.Log("foo") },
+			wantCalls:  []string{"Log"},
+			wantOutput: []string{"foo\n"},
+		},
+		{
+			name:       "Logf",
+			f:          func(mw *T) { mw.Logf("foo %d", 1) },
+			wantCalls:  []string{"Log"},
+			wantOutput: []string{"foo 1\n"},
+		},
+		{
+			name:       "Error",
+			f:          func(mw *T) { mw.Error("foo") },
+			wantCalls:  []string{"Log", "Fail"},
+			wantOutput: []string{"foo\n"},
+			wantFailed: true,
+		},
+		{
+			name:       "Fail",
+			f:          func(mw *T) { mw.Fail() },
+			wantCalls:  []string{"Fail"},
+			wantFailed: true,
+		},
+		{
+			name:        "FailNow",
+			f:           func(mw *T) { mw.FailNow() },
+			wantCalls:   []string{"Fail", "FailNow"},
+			wantFailed:  true,
+			wantAborted: true,
+		},
+		{
+			name:        "Fatalf",
+			f:           func(mw *T) { mw.Fatalf("foo %d", 1) },
+			wantCalls:   []string{"Log", "Fail", "FailNow"},
+			wantOutput:  []string{"foo 1\n"},
+			wantFailed:  true,
+			wantAborted: true,
+		},
+		{
+			name:        "SkipNow",
+			f:           func(mw *T) { mw.SkipNow() },
+			wantCalls:   []string{"SkipNow"},
+			wantSkipped: true,
+			wantAborted: true,
+		},
+		{
+			name:        "Skip",
+			f:           func(mw *T) { mw.Skip("foo") },
+			wantCalls:   []string{"Log", "SkipNow"},
+			wantOutput:  []string{"foo\n"},
+			wantSkipped: true,
+			wantAborted: true,
+		},
+		{
+			name:      "TempDir",
+			f:         func(mw *T) { mw.TempDir() },
+			wantCalls: []string{"TempDir"},
+		},
+		{
+			name:      "Parallel",
+			f:         func(mw *T) { mw.Parallel() },
+			wantCalls: []string{"Parallel"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := NewT("TestOuter", WithBaseTempdir(t.TempDir()))
+			mw := Wrap(tb)
+			reached := false
+
+			Go(func() {
+				tt.f(mw)
+				reached = true
+			})
+
+			var calls []string
+			for _, c := range tb.Calls() {
+				// Helper() calls are covered by TestWrap_attribution.
+				if c.Method != "Name" && c.Method != "Deadline" &&
+					c.Method != "Cleanup" && c.Method != "Helper" {
+					calls = append(calls, c.Method)
+				}
+			}
+			var tbOutput []string
+			for _, e := range mw.Entries() {
+				tbOutput = append(tbOutput, fmt.Sprintf(
+					"%s:%d: %s", filepath.Base(e.File), e.Line, e.Text,
+				))
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantOutput, mw.Output())
+			assert.Equal(t, tbOutput, tb.Output())
+			assert.Equal(t, tt.wantFailed, tb.isFailed())
+			assert.Equal(t, tt.wantFailed, mw.Failed())
+			assert.Equal(t, tt.wantSkipped, tb.skipped)
+			assert.Equal(t, tt.wantSkipped, mw.Skipped())
+			assert.Equal(t, tt.wantAborted, tb.Aborted())
+			assert.Equal(t, tt.wantAborted, mw.Aborted())
+			assert.Equal(t, !tt.wantAborted, reached)
+			assert.Equal(t, tb.TempDirs(), mw.TempDirs())
+		})
+	}
+}
+
+func TestWrap_Failed(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb)
+
+	tb.Fail()
+
+	assert.True(t, mw.Failed())
+	assert.Equal(t, 0, mw.FailedCount())
+}
+
+func TestWrap_owner(t *testing.T) {
+	mt := Wrap(t)
+
+	assert.Equal(t, goroutineID(), mt.owner)
+
+	mt.Run("sub", func(tb testing.TB) {
+		assert.Equal(t, goroutineID(), tb.(*T).owner)
+		assert.NotEqual(t, mt.owner, tb.(*T).owner)
+	})
+}
+
+func TestWrap_Cleanup(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb)
+	var calls []string
+	tb.Cleanup(func() { calls = append(calls, "tb") })
+	mw.Cleanup(func() { calls = append(calls, "mw 1") })
+	mw.Cleanup(func() { calls = append(calls, "mw 2") })
+
+	assert.Empty(t, calls)
+	assert.Len(t, mw.CleanupFuncs(), 2)
+
+	tb.Finish()
+
+	assert.Equal(t, []string{"tb", "mw 2", "mw 1"}, calls)
+	assert.True(t, mw.Finished())
+}
+
+func TestWrap_Run(t *testing.T) {
+	var mw *T
+	var subOK, innerOK, skippedOK bool
+	ok := t.Run("outer", func(rt *testing.T) {
+		mw = Wrap(rt)
+		mw.Cleanup(func() { mw.Log("cleanup") })
+
+		subOK = mw.Run("sub", func(t testing.TB) {
+			t.Log("in sub")
+			innerOK = t.(*T).Run("inner", func(t testing.TB) {
+				t.Helper()
+			})
+		})
+		skippedOK = mw.Run("skipped", func(t testing.TB) {
+			t.Skip("skipping")
+		})
+	})
+
+	assert.True(t, ok)
+	assert.True(t, subOK)
+	assert.True(t, innerOK)
+	assert.True(t, skippedOK)
+
+	assert.Equal(t, t.Name()+"/outer", mw.Name())
+	assert.Equal(t, []string{"cleanup\n"}, mw.Output())
+	assert.True(t, mw.Finished())
+
+	subtests := mw.Subtests()
+	require.Len(t, subtests, 2)
+	sub := subtests[0]
+	assert.Equal(t, t.Name()+"/outer/sub", sub.Name())
+	assert.Equal(t, []string{"in sub\n"}, sub.Output())
+	assert.True(t, sub.Finished())
+	require.Len(t, sub.Subtests(), 1)
+	assert.Equal(t,
+		t.Name()+"/outer/sub/inner", sub.Subtests()[0].Name(),
+	)
+	assert.Len(t, sub.Subtests()[0].HelperNames(), 1)
+
+	assert.True(t, subtests[1].Skipped())
+	assert.True(t, subtests[1].Aborted())
+}
+
+func TestWrap_Run_parallel(t *testing.T) {
+	var mux sync.Mutex
+	events := []string{}
+	record := func(s string) {
+		mux.Lock()
+		defer mux.Unlock()
+		events = append(events, s)
+	}
+
+	var mw *T
+	t.Run("outer", func(rt *testing.T) {
+		mw = Wrap(rt)
+		for _, name := range []string{"a", "b"} {
+			name := name
+			mw.Run(name, func(t testing.TB) {
+				t.(*T).Parallel()
+				record(name)
+			})
+		}
+		record("outer")
+	})
+
+	assert.Equal(t, "outer", events[0])
+	assert.ElementsMatch(t, []string{"outer", "a", "b"}, events)
+	require.Len(t, mw.Subtests(), 2)
+	assert.True(t, mw.Subtests()[0].Paralleled())
+	assert.True(t, mw.Subtests()[1].Paralleled())
+}
+
+func TestWrap_Run_mockTB(t *testing.T) {
+	tb := NewT("TestOuter")
+	mw := Wrap(tb)
+
+	ok := mw.Run("sub", func(t testing.TB) {
+		t.Error("failed")
+	})
+
+	assert.False(t, ok)
+	assert.True(t, tb.isFailed())
+	assert.Empty(t, tb.Output())
+	require.Len(t, mw.Subtests(), 1)
+	assert.Equal(t, "TestOuter/sub", mw.Subtests()[0].Name())
+	assert.Equal(t, []string{"failed\n"}, mw.Subtests()[0].Output())
+}
+
+// attributingTB is a testing.TB which attributes logged lines to source
+// locations the way *testing.T does, skipping functions which called Helper().
+type attributingTB struct {
+	testing.TB
+
+	mux     sync.Mutex
+	helpers map[string]bool
+	lines   []string
+}
+
+func (a *attributingTB) Name() string {
+	return "TestAttributing"
+}
+
+func (a *attributingTB) Cleanup(func()) {}
+
+func (a *attributingTB) Fail() {}
+
+func (a *attributingTB) Helper() {
+	pc, _, _, _ := runtime.Caller(1)
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if a.helpers == nil {
+		a.helpers = map[string]bool{}
+	}
+	a.helpers[runtime.FuncForPC(pc).Name()] = true
+}
+
+func (a *attributingTB) Log(args ...interface{}) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !a.helpers[frame.Function] || !more {
+			a.lines = append(a.lines, fmt.Sprintf(
+				"%s:%d: %s",
+				filepath.Base(frame.File), frame.Line, fmt.Sprint(args...),
+			))
+
+			return
+		}
+	}
+}
+
+func TestWrap_attribution(t *testing.T) {
+	tb := &attributingTB{}
+	mw := Wrap(tb, WithNoAbort())
+
+	var lines [5]int
+	helper := func(t testing.TB) {
+		t.Helper()
+		lines[4] = callerLine()
+		t.Logf("from %s", "helper")
+	}
+
+	lines[0] = callerLine()
+	mw.Log("direct")
+	lines[1] = callerLine()
+	mw.Skipf("skipped %d", 1)
+	lines[2] = callerLine()
+	mw.Fatal("fatal")
+	lines[3] = callerLine()
+	helper(mw)
+
+	line := func(tbLine, entryLine int, text string) string {
+		return fmt.Sprintf(
+			"wrap_test.go:%d: wrap_test.go:%d: %s", tbLine, entryLine, text,
+		)
+	}
+	assert.Equal(t, []string{
+		line(lines[0], lines[0], "direct"),
+		line(lines[1], lines[1], "skipped 1"),
+		line(lines[2], lines[2], "fatal"),
+		line(lines[4], lines[3], "from helper"),
+	}, tb.lines)
+}